@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +20,55 @@ type Config struct {
 	DBName      string
 	ServerPort  string
 	Environment string
+
+	JWTAlgorithm      string
+	JWTSecret         string
+	JWTSigningKeyFile string
+	JWTVerifyKeyFiles []string
+	JWTIssuer         string
+	JWTAudience       string
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	PasswordAlgorithm string
+	BcryptCost        int
+
+	DBAutoMigrate bool
+
+	SoftDeleteRetention time.Duration
+	PurgeInterval       time.Duration
+
+	RequireIfMatch bool
+
+	OpenAPIValidateResponses bool
+
+	IdempotencyTTL   time.Duration
+	IdempotencyLease time.Duration
+
+	LoginMaxFailures   int
+	LoginIPMaxFailures int
+	LoginLockoutBase   time.Duration
+	LoginLockoutMax    time.Duration
+
+	PasswordResetIPLimit     int
+	PasswordResetEmailLimit  int
+	PasswordResetLimitWindow time.Duration
+
+	MFAIssuer        string
+	MFAChallengeTTL  time.Duration
+	MFARequiredRoles []string
+
+	AppBaseURL       string
+	PasswordResetTTL time.Duration
+	VerifyEmailTTL   time.Duration
+	Mailer           string
+	MailFrom         string
+	MailDir          string
+	SMTPHost         string
+	SMTPPort         string
+	SMTPUser         string
+	SMTPPassword     string
 }
 
 // Load reads the environment variables and returns a Config struct.
@@ -25,15 +77,88 @@ func Load() *Config {
 	// Load .env file (only needed for local development)
 	_ = godotenv.Load()
 
+	// The embedded SQLite backend has no server to connect to
+	dbDriver := mustGet("DB_DRIVER")
+	serverGet := mustGet
+	if dbDriver == "sqlite" || dbDriver == "sqlite3" {
+		serverGet = os.Getenv
+	}
+
+	// HS256 signs with a shared secret; RS256 and EdDSA with a key file
+	jwtAlgorithm := getOrDefault("JWT_ALGORITHM", "HS256")
+	environment := getOrDefault("ENV", "dev")
+	jwtGet, keyGet := mustGet, os.Getenv
+	if jwtAlgorithm != "HS256" {
+		jwtGet, keyGet = os.Getenv, mustGet
+	}
+
+	appBaseURL := getOrDefault("APP_BASE_URL", "http://localhost:8080")
+
+	// Outside development a missing MAILER must not quietly lose every email
+	mailerGet := mustGet
+	if environment == "dev" {
+		mailerGet = func(key string) string { return getOrDefault(key, "file") }
+	}
+
 	cfg := &Config{
-		DBDriver:    mustGet("DB_DRIVER"),
-		DBHost:      mustGet("DB_HOST"),
-		DBPort:      mustGet("DB_PORT"),
-		DBUser:      mustGet("DB_USER"),
-		DBPassword:  mustGet("DB_PASSWORD"),
-		DBName:      mustGet("DB_NAME"),
+		DBDriver:    dbDriver,
+		DBHost:      serverGet("DB_HOST"),
+		DBPort:      serverGet("DB_PORT"),
+		DBUser:      serverGet("DB_USER"),
+		DBPassword:  serverGet("DB_PASSWORD"),
+		DBName:      mustGet("DB_NAME"), // file path or ":memory:" for sqlite
 		ServerPort:  mustGet("PORT"),
-		Environment: getOrDefault("ENV", "dev"),
+		Environment: environment,
+
+		JWTAlgorithm:      jwtAlgorithm,
+		JWTSecret:         jwtGet("JWT_SECRET"),
+		JWTSigningKeyFile: keyGet("JWT_SIGNING_KEY_FILE"),
+		JWTVerifyKeyFiles: getList("JWT_VERIFY_KEY_FILES"),
+		JWTIssuer:         getOrDefault("JWT_ISSUER", appBaseURL),
+		JWTAudience:       getOrDefault("JWT_AUDIENCE", "user-service"),
+
+		AccessTokenTTL:  getDurationOrDefault("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getDurationOrDefault("REFRESH_TOKEN_TTL", 7*24*time.Hour),
+
+		PasswordAlgorithm: getOrDefault("PASSWORD_ALGORITHM", "bcrypt"),
+		BcryptCost:        getIntOrDefault("BCRYPT_COST", 12),
+
+		DBAutoMigrate: getOrDefault("DB_AUTO_MIGRATE", "true") == "true",
+
+		SoftDeleteRetention: getDurationOrDefault("SOFT_DELETE_RETENTION", 30*24*time.Hour),
+		PurgeInterval:       getDurationOrDefault("PURGE_INTERVAL", time.Hour),
+
+		RequireIfMatch: getOrDefault("REQUIRE_IF_MATCH", "false") == "true",
+
+		// Checking every response against the spec is meant for development
+		OpenAPIValidateResponses: getOrDefault("OPENAPI_VALIDATE_RESPONSES", strconv.FormatBool(environment == "dev")) == "true",
+
+		IdempotencyTTL:   getDurationOrDefault("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyLease: getDurationOrDefault("IDEMPOTENCY_LEASE", time.Minute),
+
+		LoginMaxFailures:   getIntOrDefault("LOGIN_MAX_FAILURES", 5),
+		LoginIPMaxFailures: getIntOrDefault("LOGIN_IP_MAX_FAILURES", 20),
+		LoginLockoutBase:   getDurationOrDefault("LOGIN_LOCKOUT_BASE", time.Minute),
+		LoginLockoutMax:    getDurationOrDefault("LOGIN_LOCKOUT_MAX", time.Hour),
+
+		PasswordResetIPLimit:     getIntOrDefault("PASSWORD_RESET_IP_LIMIT", 20),
+		PasswordResetEmailLimit:  getIntOrDefault("PASSWORD_RESET_EMAIL_LIMIT", 3),
+		PasswordResetLimitWindow: getDurationOrDefault("PASSWORD_RESET_LIMIT_WINDOW", time.Hour),
+
+		MFAIssuer:        getOrDefault("MFA_ISSUER", "User Service"),
+		MFAChallengeTTL:  getDurationOrDefault("MFA_CHALLENGE_TTL", 5*time.Minute),
+		MFARequiredRoles: getListOrDefault("MFA_REQUIRED_ROLES", []string{"admin"}),
+
+		AppBaseURL:       appBaseURL,
+		PasswordResetTTL: getDurationOrDefault("PASSWORD_RESET_TTL", time.Hour),
+		VerifyEmailTTL:   getDurationOrDefault("VERIFY_EMAIL_TTL", 48*time.Hour),
+		Mailer:           mailerGet("MAILER"), // smtp, file or memory
+		MailFrom:         getOrDefault("MAIL_FROM", "no-reply@localhost"),
+		MailDir:          os.Getenv("MAIL_DIR"),
+		SMTPHost:         os.Getenv("SMTP_HOST"),
+		SMTPPort:         os.Getenv("SMTP_PORT"),
+		SMTPUser:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
 	}
 
 	log.Println("✅ Config loaded successfully")
@@ -58,3 +183,54 @@ func getOrDefault(key string, fallback string) string {
 	}
 	return val
 }
+
+// getList splits a comma-separated environment variable, dropping empty entries
+func getList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getListOrDefault is getList, falling back only if the variable is unset so
+// that setting it empty can clear the default
+func getListOrDefault(key string, fallback []string) []string {
+	if _, ok := os.LookupEnv(key); !ok {
+		log.Printf("⚠️  %s not set, using default: %s", key, strings.Join(fallback, ","))
+		return fallback
+	}
+	return getList(key)
+}
+
+// getDurationOrDefault parses a duration such as "15m" or "168h", falling back on a missing or invalid value
+func getDurationOrDefault(key string, fallback time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		log.Printf("⚠️  %s not set, using default: %s", key, fallback)
+		return fallback
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Printf("⚠️  %s=%q is not a valid duration, using default: %s", key, val, fallback)
+		return fallback
+	}
+	return d
+}
+
+// getIntOrDefault parses an integer environment variable, falling back on a missing or invalid value
+func getIntOrDefault(key string, fallback int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		log.Printf("⚠️  %s not set, using default: %d", key, fallback)
+		return fallback
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		log.Printf("⚠️  %s=%q is not a valid integer, using default: %d", key, val, fallback)
+		return fallback
+	}
+	return n
+}
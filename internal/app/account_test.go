@@ -0,0 +1,183 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func forgotPassword(t *testing.T, s *apptest.Server, email string) int {
+	t.Helper()
+	resp, err := s.NewClient(t).Raw().ForgotPasswordWithResponse(context.Background(), client.ForgotPasswordRequest{Email: openapi_types.Email(email)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode()
+}
+
+func resetPassword(t *testing.T, s *apptest.Server, token, password string) *client.ResetPasswordResponse {
+	t.Helper()
+	resp, err := s.NewClient(t).Raw().ResetPasswordWithResponse(context.Background(), client.ResetPasswordRequest{Token: token, NewPassword: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestForgotPasswordLimitedPerEmail(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.PasswordResetEmailLimit = 2 })
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	for range 2 {
+		if status := forgotPassword(t, s, user.Email); status != http.StatusAccepted {
+			t.Fatalf("ForgotPassword = %d, want 202", status)
+		}
+	}
+	// Case does not make it another address.
+	if status := forgotPassword(t, s, "USER@example.com"); status != http.StatusTooManyRequests {
+		t.Errorf("third reset email for one address = %d, want 429", status)
+	}
+	// Unknown addresses are limited the same way, so the 429 says nothing.
+	for range 2 {
+		forgotPassword(t, s, "nobody@example.com")
+	}
+	if status := forgotPassword(t, s, "nobody@example.com"); status != http.StatusTooManyRequests {
+		t.Errorf("third reset email for an unknown address = %d, want 429", status)
+	}
+	// Refused requests never reach the mailer, so once both sends are in no
+	// more can follow.
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.Mail.Messages()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(s.Mail.Messages()); got != 2 {
+		t.Errorf("sent %d reset emails, want 2", got)
+	}
+}
+
+func TestPasswordResetLimitedPerIP(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.PasswordResetIPLimit = 3 })
+
+	forgotPassword(t, s, "one@example.com")
+	forgotPassword(t, s, "two@example.com")
+	if resp := resetPassword(t, s, "not-a-token", "New-Passw0rd"); resp.StatusCode() != http.StatusBadRequest {
+		t.Fatalf("reset with a bad token = %d, want 400", resp.StatusCode())
+	}
+
+	// Neither 202s nor 400s are failures a login throttle would count.
+	if status := forgotPassword(t, s, "three@example.com"); status != http.StatusTooManyRequests {
+		t.Errorf("fourth request from one IP = %d, want 429", status)
+	}
+	resp := resetPassword(t, s, "not-a-token", "New-Passw0rd")
+	if resp.StatusCode() != http.StatusTooManyRequests {
+		t.Errorf("reset after the IP limit = %d, want 429", resp.StatusCode())
+	}
+	if resp.HTTPResponse.Header.Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+}
+
+func TestPasswordResetLink(t *testing.T) {
+	s := apptest.New(t)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	if status := forgotPassword(t, s, "nobody@example.com"); status != http.StatusAccepted {
+		t.Fatalf("ForgotPassword for an unknown email = %d, want 202", status)
+	}
+	if status := forgotPassword(t, s, user.Email); status != http.StatusAccepted {
+		t.Fatalf("ForgotPassword = %d, want 202", status)
+	}
+	token := linkToken(t, s.WaitForMail(t, user.Email).Body, "/password/reset")
+
+	if resp := resetPassword(t, s, token, "New-Passw0rd"); resp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("ResetPassword = %d, want 204: %s", resp.StatusCode(), resp.Body)
+	}
+	s.Login(t, user.Email, "New-Passw0rd")
+
+	// The token is bound to the old password, so it works once.
+	resp := resetPassword(t, s, token, "Other-Passw0rd")
+	if resp.StatusCode() != http.StatusBadRequest || resp.ApplicationproblemJSON400 == nil {
+		t.Fatalf("reused reset token = %d, want a 400 problem", resp.StatusCode())
+	}
+	if detail := resp.ApplicationproblemJSON400.Detail; detail == nil || *detail != "the reset token is invalid or has expired" {
+		t.Errorf("reused reset token detail = %v", detail)
+	}
+}
+
+// linkToken returns the token query parameter of the link to path in body.
+func linkToken(t *testing.T, body, path string) string {
+	t.Helper()
+	for _, field := range strings.Fields(body) {
+		link, err := url.Parse(field)
+		if err == nil && link.Path == path && link.Query().Get("token") != "" {
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatalf("no %s link in %q", path, body)
+	return ""
+}
+
+func verifyEmail(t *testing.T, s *apptest.Server, token string) int {
+	t.Helper()
+	resp, err := s.NewClient(t).Raw().VerifyEmailWithResponse(context.Background(), &client.VerifyEmailParams{Token: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode()
+}
+
+func TestEmailVerificationLink(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	c := s.Login(t, user.Email, "User-Passw0rd")
+
+	resend := func() string {
+		t.Helper()
+		sent := len(s.Mail.Messages())
+		resp, err := c.Raw().ResendVerificationWithResponse(ctx)
+		if err != nil || resp.StatusCode() != http.StatusAccepted {
+			t.Fatalf("ResendVerification: %v", err)
+		}
+		for deadline := time.Now().Add(5 * time.Second); len(s.Mail.Messages()) == sent && time.Now().Before(deadline); {
+			time.Sleep(5 * time.Millisecond)
+		}
+		return linkToken(t, s.WaitForMail(t, user.Email).Body, "/verify-email")
+	}
+
+	// A link for an address the user has since replaced proves nothing.
+	stale := resend()
+	admin := s.Login(t, adminEmail, adminPassword)
+	changed := "changed@example.com"
+	if _, err := admin.PatchUser(ctx, int(user.ID), client.UserMergePatch{Email: &changed}, ""); err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+	if status := verifyEmail(t, s, stale); status != http.StatusBadRequest {
+		t.Errorf("link for the old address = %d, want 400", status)
+	}
+	if _, err := admin.PatchUser(ctx, int(user.ID), client.UserMergePatch{Email: &user.Email}, ""); err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+
+	token := resend()
+	if status := verifyEmail(t, s, token); status != http.StatusNoContent {
+		t.Fatalf("VerifyEmail = %d, want 204", status)
+	}
+	if me, err := c.Me(ctx); err != nil || !me.EmailVerified {
+		t.Errorf("after verification Me = %+v, %v", me, err)
+	}
+	if status := verifyEmail(t, s, token); status != http.StatusBadRequest {
+		t.Errorf("reused verification link = %d, want 400", status)
+	}
+	if resp, err := c.Raw().ResendVerificationWithResponse(ctx); err != nil || resp.StatusCode() != http.StatusConflict {
+		t.Errorf("ResendVerification once verified = %v, want 409", err)
+	}
+}
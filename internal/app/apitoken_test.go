@@ -0,0 +1,88 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+)
+
+// createToken creates an API token for the signed-in c with scopes.
+func createToken(t *testing.T, c *client.Client, scopes ...client.Permission) *client.APITokenCreated {
+	t.Helper()
+	resp, err := c.Raw().CreateMyTokenWithResponse(context.Background(), client.CreateAPITokenRequest{Name: "test", Scopes: scopes})
+	if err != nil || resp.JSON201 == nil {
+		t.Fatalf("CreateMyToken: %v", err)
+	}
+	return resp.JSON201
+}
+
+func TestAPITokenLimitedToItsScopes(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	token := createToken(t, admin, client.UsersRead)
+	pat := s.NewClient(t, client.WithTokens(token.Token, ""))
+	if status := listUsers(t, pat, nil).StatusCode(); status != http.StatusOK {
+		t.Errorf("ListUsers with a users:read token = %d, want 200", status)
+	}
+
+	// The admin may write users, but this token may not.
+	_, err := pat.CreateUser(ctx, client.CreateUserRequest{Name: "Scoped", Email: "scoped@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"})
+	wantStatus(t, err, http.StatusForbidden, "CreateUser with a users:read token")
+
+	// Nor can it mint tokens of its own: that takes a login session.
+	resp, err := pat.Raw().CreateMyTokenWithResponse(ctx, client.CreateAPITokenRequest{Name: "child", Scopes: []client.Permission{client.UsersRead}})
+	if err != nil || resp.StatusCode() != http.StatusForbidden {
+		t.Errorf("CreateMyToken with an API token = %v, want 403", err)
+	}
+
+	if resp, err := admin.Raw().RevokeMyTokenWithResponse(ctx, token.Id); err != nil || resp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("RevokeMyToken: %v", err)
+	}
+	if status := listUsers(t, pat, nil).StatusCode(); status != http.StatusUnauthorized {
+		t.Errorf("ListUsers with a revoked token = %d, want 401", status)
+	}
+}
+
+func TestAPITokenScopesWithinRole(t *testing.T) {
+	s := newServer(t)
+	s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	user := s.Login(t, "user@example.com", "User-Passw0rd")
+
+	resp, err := user.Raw().CreateMyTokenWithResponse(context.Background(), client.CreateAPITokenRequest{Name: "escalate", Scopes: []client.Permission{client.UsersWrite}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusUnprocessableEntity {
+		t.Errorf("user creating a users:write token = %d, want 422", resp.StatusCode())
+	}
+}
+
+func TestServiceAccountUsesTokensOnly(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	account, err := admin.Raw().CreateServiceAccountWithResponse(ctx, client.CreateServiceAccountRequest{Name: "Nightly export", Role: "viewer"})
+	if err != nil || account.JSON201 == nil {
+		t.Fatalf("CreateServiceAccount: %v", err)
+	}
+	if !account.JSON201.ServiceAccount {
+		t.Error("CreateServiceAccount returned a regular user")
+	}
+
+	token, err := admin.Raw().CreateUserTokenWithResponse(ctx, account.JSON201.Id, client.CreateAPITokenRequest{Name: "export", Scopes: []client.Permission{client.UsersRead}})
+	if err != nil || token.JSON201 == nil {
+		t.Fatalf("CreateUserToken: %v", err)
+	}
+	pat := s.NewClient(t, client.WithTokens(token.JSON201.Token, ""))
+	if status := listUsers(t, pat, nil).StatusCode(); status != http.StatusOK {
+		t.Errorf("ListUsers as the service account = %d, want 200", status)
+	}
+
+	if me, err := pat.Me(ctx); err != nil || me.Id != account.JSON201.Id {
+		t.Errorf("Me with the service account's token = %v, %v", me, err)
+	}
+}
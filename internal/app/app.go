@@ -0,0 +1,106 @@
+// Package app wires the repositories, services, controllers and middleware
+// into the HTTP handler of the user service.
+package app
+
+import (
+	"fmt"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/controller"
+	"go-crud-oapi/internal/handler"
+	"go-crud-oapi/internal/middleware"
+	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/internal/router"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/mailer"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// App is the assembled service. Handler serves the API; the other fields are
+// what main needs to seed the database and run background jobs.
+type App struct {
+	Handler     http.Handler
+	Hasher      *auth.PasswordHasher
+	Users       service.UserServiceInterFace
+	Idempotency repository.IdempotencyRepoInterface
+}
+
+// New builds the service on dbConn, whose schema must be up to date, sending
+// email through mail.
+func New(cfg *config.Config, dbConn *gorm.DB, mail mailer.Mailer) (*App, error) {
+	hasher, err := auth.NewPasswordHasher(cfg.PasswordAlgorithm, cfg.BcryptCost)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password hashing config: %w", err)
+	}
+	keys, err := auth.NewKeyManager(auth.KeyConfig{
+		Algorithm:      cfg.JWTAlgorithm,
+		Secret:         cfg.JWTSecret,
+		SigningKeyFile: cfg.JWTSigningKeyFile,
+		VerifyKeyFiles: cfg.JWTVerifyKeyFiles,
+		Issuer:         cfg.JWTIssuer,
+		Audience:       cfg.JWTAudience,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT signing config: %w", err)
+	}
+
+	repo := repository.NewUserRepository(dbConn)
+	tokenRepo := repository.NewTokenRepository(dbConn)
+	auditRepo := repository.NewAuditRepository(dbConn)
+	idempotencyRepo := repository.NewIdempotencyRepository(dbConn)
+	mfaRepo := repository.NewMFARepository(dbConn)
+	apiTokenRepo := repository.NewAPITokenRepository(dbConn)
+	tx := repository.NewTransactor(dbConn)
+
+	accountLockout := auth.Backoff{Threshold: cfg.LoginMaxFailures, Base: cfg.LoginLockoutBase, Max: cfg.LoginLockoutMax}
+	ipLockout := auth.Backoff{Threshold: cfg.LoginIPMaxFailures, Base: cfg.LoginLockoutBase, Max: cfg.LoginLockoutMax}
+
+	auditSvc := service.NewAuditService(auditRepo)
+	svc := service.NewUserService(repo, tx, hasher, auditSvc)
+	mfaSvc := service.NewMFAService(repo, mfaRepo, hasher, tx, auditSvc, cfg.MFAIssuer)
+	authSvc := service.NewAuthService(repo, tokenRepo, hasher, keys, cfg.AccessTokenTTL, cfg.RefreshTokenTTL, accountLockout, mfaSvc, cfg.MFAChallengeTTL)
+	apiTokenSvc := service.NewAPITokenService(repo, apiTokenRepo, tx, auditSvc)
+	accountSvc := service.NewAccountService(repo, tokenRepo, hasher, keys, mail, tx, auditSvc, cfg.AppBaseURL,
+		service.AccountTTLs{PasswordReset: cfg.PasswordResetTTL, VerifyEmail: cfg.VerifyEmailTTL})
+
+	jwtAuth := middleware.NewJWTAuth(keys, authSvc, apiTokenSvc)
+	requireMFA := middleware.RequireMFA(cfg.MFARequiredRoles...)
+	idempotency := middleware.NewIdempotency(idempotencyRepo, cfg.IdempotencyTTL, cfg.IdempotencyLease)
+	loginThrottle := middleware.NewLoginThrottle(ipLockout)
+	resetLimits := router.ResetLimits{
+		IP:    middleware.NewRequestLimit(cfg.PasswordResetIPLimit, cfg.PasswordResetLimitWindow),
+		Email: middleware.NewRequestLimit(cfg.PasswordResetEmailLimit, cfg.PasswordResetLimitWindow),
+	}
+
+	// Inject all controllers to router
+	server := &controller.Server{
+		UserController:     controller.NewUserController(svc, accountSvc, cfg.RequireIfMatch),
+		AuthController:     controller.NewAuthController(repo, authSvc),
+		AccountController:  controller.NewAccountController(accountSvc, svc),
+		MFAController:      controller.NewMFAController(mfaSvc),
+		APITokenController: controller.NewAPITokenController(apiTokenSvc),
+		AuditController:    controller.NewAuditController(auditSvc),
+		JWKSController:     controller.NewJWKSController(keys),
+	}
+	spec, err := handler.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	docsController, err := controller.NewDocsController(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	openAPI, err := middleware.NewOpenAPIValidator(spec, cfg.OpenAPIValidateResponses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+
+	return &App{
+		Handler:     router.NewRouter(server, docsController, openAPI, jwtAuth, requireMFA, idempotency, loginThrottle, resetLimits),
+		Hasher:      hasher,
+		Users:       svc,
+		Idempotency: idempotencyRepo,
+	}, nil
+}
@@ -0,0 +1,193 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// lastAudit returns the newest audit entry for action.
+func lastAudit(t *testing.T, c *client.Client, action client.AuditAction) client.AuditLog {
+	t.Helper()
+	for entry, err := range c.AuditLogs(context.Background(), &client.ListAuditLogsParams{Action: &action}) {
+		if err != nil {
+			t.Fatalf("AuditLogs: %v", err)
+		}
+		return entry
+	}
+	t.Fatalf("no %s entry in the audit log", action)
+	return client.AuditLog{}
+}
+
+// wantChange checks that entry records field going from old to new; a nil
+// side must be absent.
+func wantChange(t *testing.T, entry client.AuditLog, field string, old, new any) {
+	t.Helper()
+	if entry.Changes == nil {
+		t.Fatalf("%s has no changes, want %s", entry.Action, field)
+	}
+	change, ok := (*entry.Changes)[field]
+	if !ok {
+		t.Fatalf("%s changes %v, want %s", entry.Action, *entry.Changes, field)
+	}
+	value := func(v *any) any {
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	if got := value(change.Old); old != nil && got != old || old == nil && got != nil {
+		t.Errorf("%s %s old = %v, want %v", entry.Action, field, got, old)
+	}
+	if got := value(change.New); new != nil && got != new || new == nil && got != nil {
+		t.Errorf("%s %s new = %v, want %v", entry.Action, field, got, new)
+	}
+}
+
+func TestAuditRecordsActorIDs(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+	me, err := admin.Me(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := client.CreateUserRequest{Name: "Audited", Email: "one@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"}
+	if _, err := admin.CreateUser(ctx, create); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	entry := lastAudit(t, admin, client.UserCreate)
+	if entry.Actor != "user" || entry.ActorUserId == nil || *entry.ActorUserId != me.Id || entry.ActorTokenId != nil {
+		t.Errorf("signed-in create recorded as %s %v token %v, want user %d", entry.Actor, entry.ActorUserId, entry.ActorTokenId, me.Id)
+	}
+
+	created, err := admin.Raw().CreateMyTokenWithResponse(ctx, client.CreateAPITokenRequest{Name: "ci", Scopes: []client.Permission{client.UsersWrite}})
+	if err != nil || created.JSON201 == nil {
+		t.Fatalf("CreateMyToken: %v", err)
+	}
+	pat := s.NewClient(t, client.WithTokens(created.JSON201.Token, ""))
+	create.Email, create.Phone = openapi_types.Email("two@example.com"), "+14155550101"
+	if _, err := pat.CreateUser(ctx, create); err != nil {
+		t.Fatalf("CreateUser with an API token: %v", err)
+	}
+	entry = lastAudit(t, admin, client.UserCreate)
+	if entry.ActorUserId == nil || *entry.ActorUserId != me.Id || entry.ActorTokenId == nil || *entry.ActorTokenId != created.JSON201.Id {
+		t.Errorf("create with an API token recorded as user %v token %v, want user %d token %d", entry.ActorUserId, entry.ActorTokenId, me.Id, created.JSON201.Id)
+	}
+
+	// Filtering goes by ID too.
+	var mine int
+	for e, err := range admin.AuditLogs(ctx, &client.ListAuditLogsParams{ActorUserId: &me.Id}) {
+		if err != nil {
+			t.Fatalf("AuditLogs: %v", err)
+		}
+		if e.ActorUserId == nil || *e.ActorUserId != me.Id {
+			t.Errorf("filtered by actor %d, got entry by %v", me.Id, e.ActorUserId)
+		}
+		mine++
+	}
+	if mine != 3 {
+		t.Errorf("found %d entries by the admin, want 2 creates and a token", mine)
+	}
+
+	// A password reset is made by nobody signed in.
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	forgotPassword(t, s, user.Email)
+	token := linkToken(t, s.WaitForMail(t, user.Email).Body, "/password/reset")
+	if resp := resetPassword(t, s, token, "New-Passw0rd"); resp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("ResetPassword = %d", resp.StatusCode())
+	}
+	entry = lastAudit(t, admin, client.UserPasswordReset)
+	if entry.Actor != "system" || entry.ActorUserId != nil {
+		t.Errorf("password reset recorded as %s %v, want system", entry.Actor, entry.ActorUserId)
+	}
+}
+
+func TestAuditRecordsWhatChanged(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	c := s.Login(t, user.Email, "User-Passw0rd")
+
+	now := time.Now()
+	secret, _ := enrollTOTP(t, c, "User-Passw0rd", now)
+	wantChange(t, lastAudit(t, admin, client.UserMfaEnable), "mfa_enabled", false, true)
+
+	disable := code(t, secret, now.Add(30*time.Second))
+	if resp, err := c.Raw().DisableTOTPWithResponse(ctx, client.MFACodeRequest{Code: disable}); err != nil || resp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("DisableTOTP: %v", err)
+	}
+	wantChange(t, lastAudit(t, admin, client.UserMfaDisable), "mfa_enabled", true, false)
+
+	created, err := c.Raw().CreateMyTokenWithResponse(ctx, client.CreateAPITokenRequest{Name: "ci", Scopes: []client.Permission{client.UsersRead}})
+	if err != nil || created.JSON201 == nil {
+		t.Fatalf("CreateMyToken: %v", err)
+	}
+	id := float64(created.JSON201.Id)
+	wantChange(t, lastAudit(t, admin, client.UserTokenCreate), "api_token", nil, id)
+	if resp, err := c.Raw().RevokeMyTokenWithResponse(ctx, created.JSON201.Id); err != nil || resp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("RevokeMyToken: %v", err)
+	}
+	wantChange(t, lastAudit(t, admin, client.UserTokenRevoke), "api_token", id, nil)
+
+	for range s.Config.LoginMaxFailures {
+		loginProblem(t, s, user.Email, "Wrong-Passw0rd")
+	}
+	if resp, err := admin.Raw().UnlockUserWithResponse(ctx, int(user.ID)); err != nil || resp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("UnlockUser: %v", err)
+	}
+	unlock := lastAudit(t, admin, client.UserUnlock)
+	wantChange(t, unlock, "failed_logins", float64(s.Config.LoginMaxFailures), float64(0))
+	if _, ok := (*unlock.Changes)["locked_until"]; !ok {
+		t.Error("unlock does not record the lockout it cleared")
+	}
+}
+
+func TestEveryUserMutationIsAudited(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	user, err := admin.CreateUser(ctx, client.CreateUserRequest{Name: "Audited", Email: "audited@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	wantChange(t, lastAudit(t, admin, client.UserCreate), "email", nil, "audited@example.com")
+
+	name := "Renamed"
+	if _, err := admin.PatchUser(ctx, user.Id, client.UserMergePatch{Name: &name}, ""); err != nil {
+		t.Fatalf("PatchUser: %v", err)
+	}
+	update := lastAudit(t, admin, client.UserUpdate)
+	wantChange(t, update, "name", "Audited", "Renamed")
+	if len(*update.Changes) != 1 {
+		t.Errorf("update records %v, want only the name", *update.Changes)
+	}
+
+	if err := admin.DeleteUser(ctx, user.Id, ""); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	wantChange(t, lastAudit(t, admin, client.UserDelete), "name", "Renamed", nil)
+	if _, err := admin.RestoreUser(ctx, user.Id); err != nil {
+		t.Fatalf("RestoreUser: %v", err)
+	}
+	restore := lastAudit(t, admin, client.UserRestore)
+	wantChange(t, restore, "deleted", true, false)
+	if restore.TargetUserId == nil || *restore.TargetUserId != user.Id {
+		t.Errorf("restore targets %v, want %d", restore.TargetUserId, user.Id)
+	}
+
+	// The log cannot be rewritten, even with direct database access.
+	if err := s.DB.Exec("UPDATE audit_logs SET actor = 'someone else'").Error; err == nil {
+		t.Error("audit entries could be updated")
+	}
+	if err := s.DB.Exec("DELETE FROM audit_logs").Error; err == nil {
+		t.Error("audit entries could be deleted")
+	}
+}
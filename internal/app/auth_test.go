@@ -0,0 +1,133 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+const (
+	adminEmail    = "admin@example.com"
+	adminPassword = "Admin-Passw0rd"
+)
+
+func newServer(t *testing.T) *apptest.Server {
+	s := apptest.New(t)
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	return s
+}
+
+// refresh spends refreshToken and returns the new pair, or the status of the
+// rejection.
+func refresh(t *testing.T, s *apptest.Server, refreshToken string) (*client.AuthTokens, int) {
+	t.Helper()
+	resp, err := s.NewClient(t).Raw().RefreshTokenWithResponse(context.Background(), client.RefreshRequest{RefreshToken: &refreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	return resp.JSON200, resp.StatusCode()
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	s := newServer(t)
+	_, first := s.Login(t, adminEmail, adminPassword).Tokens()
+
+	second, status := refresh(t, s, first)
+	if second == nil {
+		t.Fatalf("refresh = %d, want 200", status)
+	}
+	if second.RefreshToken == first {
+		t.Fatal("refresh returned the same refresh token")
+	}
+
+	third, status := refresh(t, s, second.RefreshToken)
+	if third == nil {
+		t.Fatalf("refresh with the rotated token = %d, want 200", status)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	s := newServer(t)
+	_, first := s.Login(t, adminEmail, adminPassword).Tokens()
+
+	second, _ := refresh(t, s, first)
+	if second == nil {
+		t.Fatal("refresh failed")
+	}
+
+	// Presenting a spent token means it was copied; the whole chain ends.
+	if _, status := refresh(t, s, first); status != http.StatusUnauthorized {
+		t.Fatalf("reused refresh token = %d, want 401", status)
+	}
+	if _, status := refresh(t, s, second.RefreshToken); status != http.StatusUnauthorized {
+		t.Fatalf("refresh token issued before the reuse = %d, want 401", status)
+	}
+}
+
+func TestConcurrentRefreshWithOneToken(t *testing.T) {
+	s := newServer(t)
+	_, token := s.Login(t, adminEmail, adminPassword).Tokens()
+
+	// Two holders of the same token race to spend it. Only one may win, and
+	// the loser's attempt counts as reuse.
+	var wg sync.WaitGroup
+	results := make([]*client.AuthTokens, 2)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = refresh(t, s, token)
+		}()
+	}
+	wg.Wait()
+
+	var winner *client.AuthTokens
+	for _, r := range results {
+		if r != nil {
+			if winner != nil {
+				t.Fatal("both refreshes succeeded with one token")
+			}
+			winner = r
+		}
+	}
+	if winner == nil {
+		t.Fatal("neither refresh succeeded")
+	}
+	if _, status := refresh(t, s, winner.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("winner's refresh token = %d after the reuse, want 401", status)
+	}
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	c := s.Login(t, adminEmail, adminPassword)
+	access, refreshToken := c.Tokens()
+
+	if err := c.Logout(ctx); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	resp, err := s.NewClient(t, client.WithTokens(access, "")).Raw().GetMeWithResponse(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusUnauthorized {
+		t.Errorf("access token after logout = %d, want 401", resp.StatusCode())
+	}
+	if _, status := refresh(t, s, refreshToken); status != http.StatusUnauthorized {
+		t.Errorf("refresh token after logout = %d, want 401", status)
+	}
+}
+
+func TestAccessTokenIsNotARefreshToken(t *testing.T) {
+	s := newServer(t)
+	access, _ := s.Login(t, adminEmail, adminPassword).Tokens()
+
+	if _, status := refresh(t, s, access); status != http.StatusUnauthorized {
+		t.Errorf("refresh with an access token = %d, want 401", status)
+	}
+}
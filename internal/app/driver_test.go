@@ -0,0 +1,42 @@
+package app_test
+
+import (
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/internal/db"
+	"path/filepath"
+	"testing"
+)
+
+func TestDriverNames(t *testing.T) {
+	for name, want := range map[string]string{
+		"postgres": "postgres", "postgresql": "postgres",
+		"mysql":  "mysql",
+		"sqlite": "sqlite", "sqlite3": "sqlite",
+	} {
+		driver, err := db.NewDriver(name)
+		if err != nil || driver.Name() != want {
+			t.Errorf("NewDriver(%q) = %v, %v, want the %s driver", name, driver, err, want)
+		}
+	}
+	if _, err := db.NewDriver("oracle"); err == nil {
+		t.Error("NewDriver accepted an unsupported driver")
+	}
+}
+
+func TestSQLiteFileIsShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.db")
+	onFile := func(cfg *config.Config) { cfg.DBName = path }
+
+	first := apptest.New(t, onFile)
+	first.CreateUser(t, "admin", adminEmail, adminPassword)
+
+	// A second instance on the same file finds the schema up to date and the
+	// user already there.
+	second := apptest.New(t, onFile)
+	m := migrator(t, second)
+	if current, err := m.CurrentVersion(t.Context()); err != nil || current != m.LatestVersion() {
+		t.Errorf("second instance at schema version %d (%v)", current, err)
+	}
+	second.Login(t, adminEmail, adminPassword)
+}
@@ -0,0 +1,65 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/client"
+	"testing"
+)
+
+func TestResponsesNeverIncludePasswordHash(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+	raw := admin.Raw()
+
+	created, err := raw.CreateUserWithResponse(ctx, nil, client.CreateUserRequest{Name: "Hidden", Email: "hidden@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"})
+	if err != nil || created.JSON201 == nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	id := created.JSON201.Id
+	var stored model.User
+	if err := s.DB.First(&stored, id).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	withDeleted := true
+	bodies := map[string][]byte{"CreateUser": created.Body}
+	get, err := raw.GetUserWithResponse(ctx, id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodies["GetUser"] = get.Body
+	list, err := raw.ListUsersWithResponse(ctx, &client.ListUsersParams{IncludeDeleted: &withDeleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodies["ListUsers"] = list.Body
+	me, err := s.Login(t, "hidden@example.com", "User-Passw0rd").Raw().GetMeWithResponse(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodies["GetMe"] = me.Body
+
+	for name, body := range bodies {
+		if bytes.Contains(body, []byte(stored.Password)) || bytes.Contains(body, []byte(`"password"`)) {
+			t.Errorf("%s response includes the password: %s", name, body)
+		}
+	}
+
+	// Nothing beyond the documented fields, such as lockout counters or the
+	// TOTP secret, is serialized.
+	var fields map[string]any
+	if err := json.Unmarshal(get.Body, &fields); err != nil {
+		t.Fatal(err)
+	}
+	allowed := map[string]bool{"id": true, "name": true, "email": true, "phone": true, "age": true, "role": true,
+		"version": true, "email_verified": true, "service_account": true, "deleted_at": true, "locked_until": true}
+	for field := range fields {
+		if !allowed[field] {
+			t.Errorf("GetUser returns undocumented field %q", field)
+		}
+	}
+}
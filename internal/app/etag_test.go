@@ -0,0 +1,72 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+)
+
+func wantStatus(t *testing.T, err error, status int, what string) {
+	t.Helper()
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+		t.Errorf("%s = %v, want %d", what, err, status)
+	}
+}
+
+func TestStaleETagIsRejected(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	id := int(user.ID)
+
+	_, etag, err := admin.GetUser(ctx, id)
+	if err != nil || etag == "" {
+		t.Fatalf("GetUser returned ETag %q: %v", etag, err)
+	}
+
+	unchanged, err := admin.Raw().GetUserWithResponse(ctx, id, &client.GetUserParams{IfNoneMatch: &etag})
+	if err != nil || unchanged.StatusCode() != http.StatusNotModified {
+		t.Errorf("GetUser with a current If-None-Match = %v, want 304", err)
+	}
+
+	first := "First"
+	if _, err := admin.PatchUser(ctx, id, client.UserMergePatch{Name: &first}, etag); err != nil {
+		t.Fatalf("PatchUser with the current ETag: %v", err)
+	}
+
+	// A second writer that read before the first patch loses.
+	second := "Second"
+	_, err = admin.PatchUser(ctx, id, client.UserMergePatch{Name: &second}, etag)
+	wantStatus(t, err, http.StatusPreconditionFailed, "PatchUser with a stale ETag")
+	_, err = admin.UpdateUser(ctx, id, client.UpdateUserRequest{Name: second, Email: "user@example.com", Phone: user.Phone, Role: "user"}, etag)
+	wantStatus(t, err, http.StatusPreconditionFailed, "UpdateUser with a stale ETag")
+	wantStatus(t, admin.DeleteUser(ctx, id, etag), http.StatusPreconditionFailed, "DeleteUser with a stale ETag")
+
+	view, current, err := admin.GetUser(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := view.AsUserFull(); got.Name != "First" || current == etag {
+		t.Errorf("after the rejected writes the user is %q with ETag %s", got.Name, current)
+	}
+	if err := admin.DeleteUser(ctx, id, current); err != nil {
+		t.Errorf("DeleteUser with the current ETag: %v", err)
+	}
+}
+
+func TestIfMatchCanBeRequired(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.RequireIfMatch = true })
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	admin := s.Login(t, adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	name := "Blind"
+	_, err := admin.PatchUser(context.Background(), int(user.ID), client.UserMergePatch{Name: &name}, "")
+	wantStatus(t, err, http.StatusPreconditionRequired, "PatchUser without If-Match")
+}
@@ -0,0 +1,75 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func storedHash(t *testing.T, s *apptest.Server, email string) string {
+	t.Helper()
+	var user model.User
+	if err := s.DB.First(&user, "email = ?", email).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user.Password
+}
+
+func TestPasswordsAreHashedOnWrite(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	if _, err := admin.CreateUser(ctx, client.CreateUserRequest{Name: "Hashed", Email: "hashed@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	hash := storedHash(t, s, "hashed@example.com")
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte("User-Passw0rd")) != nil {
+		t.Fatalf("stored password %q is not a bcrypt hash of the one given", hash)
+	}
+
+	user := s.Login(t, "hashed@example.com", "User-Passw0rd")
+	resp, err := user.Raw().ChangeMyPasswordWithResponse(ctx, client.ChangePasswordRequest{OldPassword: "User-Passw0rd", NewPassword: "Changed-Passw0rd"})
+	if err != nil || resp.StatusCode() != http.StatusNoContent {
+		t.Fatalf("ChangeMyPassword: %v", err)
+	}
+	if changed := storedHash(t, s, "hashed@example.com"); changed == hash || bcrypt.CompareHashAndPassword([]byte(changed), []byte("Changed-Passw0rd")) != nil {
+		t.Errorf("changed password stored as %q", changed)
+	}
+}
+
+func TestLoginUpgradesPasswordHash(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.PasswordAlgorithm = "argon2id" })
+
+	// A hash from before the switch to argon2id.
+	legacy, err := bcrypt.GenerateFromPassword([]byte("User-Passw0rd"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := s.CreateUser(t, "user", "legacy@example.com", "User-Passw0rd")
+	if err := s.DB.Model(user).Update("password", string(legacy)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	loginProblem(t, s, user.Email, "Wrong-Passw0rd")
+	if hash := storedHash(t, s, user.Email); hash != string(legacy) {
+		t.Fatal("a failed login replaced the hash")
+	}
+
+	s.Login(t, user.Email, "User-Passw0rd")
+	hash := storedHash(t, s, user.Email)
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		t.Fatalf("hash after login = %q, want argon2id", hash)
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+	if again := storedHash(t, s, user.Email); again != hash {
+		t.Error("a current hash was replaced again")
+	}
+}
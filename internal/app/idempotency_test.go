@@ -0,0 +1,129 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createWithKey creates a user with Idempotency-Key key.
+func createWithKey(t *testing.T, c *client.Client, key string, req client.CreateUserRequest) *client.CreateUserResponse {
+	t.Helper()
+	resp, err := c.Raw().CreateUserWithResponse(context.Background(), &client.CreateUserParams{IdempotencyKey: &key}, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestIdempotentCreateReplays(t *testing.T) {
+	s := newServer(t)
+	admin := s.Login(t, adminEmail, adminPassword)
+	req := client.CreateUserRequest{Name: "Idempotent", Email: "idem@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"}
+
+	first := createWithKey(t, admin, "create-1", req)
+	if first.JSON201 == nil {
+		t.Fatalf("CreateUser = %d, want 201", first.StatusCode())
+	}
+	again := createWithKey(t, admin, "create-1", req)
+	if again.JSON201 == nil || again.JSON201.Id != first.JSON201.Id {
+		t.Fatalf("retried CreateUser = %d, want the first 201 again", again.StatusCode())
+	}
+	if again.HTTPResponse.Header.Get("Idempotent-Replayed") != "true" {
+		t.Error("replay is not marked Idempotent-Replayed")
+	}
+
+	req.Name = "Someone Else"
+	if resp := createWithKey(t, admin, "create-1", req); resp.StatusCode() != http.StatusConflict {
+		t.Errorf("key reused with another body = %d, want 409", resp.StatusCode())
+	}
+
+	// Keys are per caller, so another user's key cannot replay this answer.
+	s.CreateUser(t, "admin", "other@example.com", adminPassword)
+	other := s.Login(t, "other@example.com", adminPassword)
+	req.Name = "Idempotent"
+	if resp := createWithKey(t, other, "create-1", req); resp.StatusCode() != http.StatusConflict || resp.HTTPResponse.Header.Get("Idempotent-Replayed") != "" {
+		t.Errorf("another caller's create with the same key = %d, want a fresh 409 for the taken email", resp.StatusCode())
+	}
+}
+
+// failIdempotencyWrites makes the database refuse to change stored
+// idempotency records with statement, "UPDATE" or "DELETE", until the
+// returned func is called.
+func failIdempotencyWrites(t *testing.T, s *apptest.Server, statement string) func() {
+	t.Helper()
+	trigger := "idempotency_records_no_" + strings.ToLower(statement)
+	if err := s.DB.Exec("CREATE TRIGGER " + trigger + " BEFORE " + statement + " ON idempotency_records BEGIN SELECT RAISE(ABORT, 'refused'); END").Error; err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if err := s.DB.Exec("DROP TRIGGER " + trigger).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIdempotencyKeyReleasedWhenAnswerNotStored(t *testing.T) {
+	s := newServer(t)
+	admin := s.Login(t, adminEmail, adminPassword, client.WithRetries(0, 0))
+	req := client.CreateUserRequest{Name: "Idempotent", Email: "idem@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"}
+
+	failIdempotencyWrites(t, s, "UPDATE")
+	if resp := createWithKey(t, admin, "create-1", req); resp.JSON201 == nil {
+		t.Fatalf("CreateUser = %d, want 201", resp.StatusCode())
+	}
+
+	// With no answer to replay the retry must run again, which here finds
+	// the email taken, rather than wait for a request that has finished.
+	resp := createWithKey(t, admin, "create-1", req)
+	if resp.StatusCode() != http.StatusConflict || resp.HTTPResponse.Header.Get("Retry-After") != "" {
+		t.Errorf("retry after the answer was lost = %d (Retry-After %q), want the create to run again", resp.StatusCode(), resp.HTTPResponse.Header.Get("Retry-After"))
+	}
+}
+
+func TestIdempotencyKeyLeaseExpires(t *testing.T) {
+	const lease = 200 * time.Millisecond
+	s := apptest.New(t, func(cfg *config.Config) { cfg.IdempotencyLease = lease })
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	// The client would wait out the 409's Retry-After and retry on its own.
+	admin := s.Login(t, adminEmail, adminPassword, client.WithRetries(0, 0))
+	req := client.CreateUserRequest{Name: "Idempotent", Email: "idem@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"}
+
+	// Neither store nor release the answer, as if the process had died.
+	allowUpdate := failIdempotencyWrites(t, s, "UPDATE")
+	allowDelete := failIdempotencyWrites(t, s, "DELETE")
+	if resp := createWithKey(t, admin, "create-1", req); resp.JSON201 == nil {
+		t.Fatalf("CreateUser = %d, want 201", resp.StatusCode())
+	}
+	allowUpdate()
+	allowDelete()
+	if err := s.DB.Unscoped().Where("email = ?", req.Email).Delete(&model.User{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	resp := createWithKey(t, admin, "create-1", req)
+	if resp.StatusCode() != http.StatusConflict || resp.HTTPResponse.Header.Get("Retry-After") == "" {
+		t.Fatalf("retry during the lease = %d, want 409 with Retry-After", resp.StatusCode())
+	}
+
+	time.Sleep(lease)
+	resp = createWithKey(t, admin, "create-1", req)
+	if resp.JSON201 == nil || resp.HTTPResponse.Header.Get("Idempotent-Replayed") != "" {
+		t.Fatalf("retry after the lease = %d, want the create to run again", resp.StatusCode())
+	}
+
+	// Once answered, the key is kept for the TTL rather than the lease.
+	var record model.IdempotencyRecord
+	if err := s.DB.First(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(record.ExpiresAt) < time.Minute {
+		t.Errorf("answered key expires in %s, want the TTL", time.Until(record.ExpiresAt))
+	}
+}
@@ -0,0 +1,223 @@
+package app_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/client"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeKey generates a private key for alg and writes it as PKCS#8 PEM.
+func writeKey(t *testing.T, alg string) string {
+	t.Helper()
+
+	var key crypto.Signer
+	var err error
+	switch alg {
+	case "RS256":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "EdDSA":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func jwks(t *testing.T, s *apptest.Server) *client.JWKS {
+	t.Helper()
+	resp, err := s.NewClient(t).Raw().GetJWKSWithResponse(context.Background())
+	if err != nil || resp.JSON200 == nil {
+		t.Fatalf("GetJWKS: %v", err)
+	}
+	return resp.JSON200
+}
+
+// publicKey finds kid in set and decodes it the way another service would.
+func publicKey(t *testing.T, set *client.JWKS, kid string) any {
+	t.Helper()
+	decode := func(s *string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(*s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	for _, k := range set.Keys {
+		if k.Kid == nil || *k.Kid != kid {
+			continue
+		}
+		switch *k.Kty {
+		case "RSA":
+			return &rsa.PublicKey{N: new(big.Int).SetBytes(decode(k.N)), E: int(new(big.Int).SetBytes(decode(k.E)).Int64())}
+		case "OKP":
+			return ed25519.PublicKey(decode(k.X))
+		}
+	}
+	t.Fatalf("kid %q is not in the JWKS", kid)
+	return nil
+}
+
+func getMe(t *testing.T, s *apptest.Server, access string) int {
+	t.Helper()
+	resp, err := s.NewClient(t, client.WithTokens(access, "")).Raw().GetMeWithResponse(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode()
+}
+
+func TestAccessTokenVerifiesAgainstJWKS(t *testing.T) {
+	for _, alg := range []string{"RS256", "EdDSA"} {
+		t.Run(alg, func(t *testing.T) {
+			s := apptest.New(t, func(cfg *config.Config) {
+				cfg.JWTAlgorithm = alg
+				cfg.JWTSigningKeyFile = writeKey(t, alg)
+			})
+			s.CreateUser(t, "admin", adminEmail, adminPassword)
+			access, _ := s.Login(t, adminEmail, adminPassword).Tokens()
+
+			set := jwks(t, s)
+			token, err := jwt.Parse(access, func(token *jwt.Token) (any, error) {
+				kid, _ := token.Header["kid"].(string)
+				return publicKey(t, set, kid), nil
+			}, jwt.WithValidMethods([]string{alg}), jwt.WithIssuer(s.Config.JWTIssuer), jwt.WithAudience(s.Config.JWTAudience))
+			if err != nil || !token.Valid {
+				t.Fatalf("access token does not verify against the JWKS: %v", err)
+			}
+		})
+	}
+}
+
+func TestRetiredSigningKeyStillVerifies(t *testing.T) {
+	retired, current, unknown := writeKey(t, "EdDSA"), writeKey(t, "EdDSA"), writeKey(t, "EdDSA")
+	s := apptest.New(t, func(cfg *config.Config) {
+		cfg.JWTAlgorithm = "EdDSA"
+		cfg.JWTSigningKeyFile = current
+		cfg.JWTVerifyKeyFiles = []string{retired}
+	})
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	sign := func(keyFile string) string {
+		keys, err := auth.NewKeyManager(auth.KeyConfig{
+			Algorithm:      "EdDSA",
+			SigningKeyFile: keyFile,
+			Issuer:         s.Config.JWTIssuer,
+			Audience:       s.Config.JWTAudience,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := keys.GenerateToken(user.ID, user.Email, user.Role, []string{"pwd"}, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	if got := len(jwks(t, s).Keys); got != 2 {
+		t.Errorf("JWKS has %d keys, want the current and the retired one", got)
+	}
+	if status := getMe(t, s, sign(retired)); status != http.StatusOK {
+		t.Errorf("token from the retired key = %d, want 200", status)
+	}
+	if status := getMe(t, s, sign(unknown)); status != http.StatusUnauthorized {
+		t.Errorf("token from an unknown key = %d, want 401", status)
+	}
+}
+
+func TestTokenAlgorithmMustMatchKey(t *testing.T) {
+	keyFile := writeKey(t, "RS256")
+	s := apptest.New(t, func(cfg *config.Config) {
+		cfg.JWTAlgorithm = "RS256"
+		cfg.JWTSigningKeyFile = keyFile
+	})
+	user := s.CreateUser(t, "admin", adminEmail, adminPassword)
+	set := jwks(t, s)
+	kid := *set.Keys[0].Kid
+
+	// HS256 "signed" with the published public key must not pass for RS256.
+	pub, err := x509.MarshalPKIXPublicKey(publicKey(t, set, kid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":       "1",
+		"email":     user.Email,
+		"role":      user.Role,
+		"token_use": auth.TokenUseAccess,
+		"iss":       s.Config.JWTIssuer,
+		"aud":       s.Config.JWTAudience,
+		"jti":       "forged",
+		"exp":       time.Now().Add(time.Minute).Unix(),
+	})
+	forged.Header["kid"] = kid
+	token, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status := getMe(t, s, token); status != http.StatusUnauthorized {
+		t.Errorf("HS256 token with an RS256 kid = %d, want 401", status)
+	}
+}
+
+func TestSharedSecretIsNotPublished(t *testing.T) {
+	s := newServer(t)
+	if keys := jwks(t, s).Keys; len(keys) != 0 {
+		t.Errorf("JWKS for HS256 has %d keys, want none", len(keys))
+	}
+}
+
+func TestChallengeTokenIsNotAnAccessToken(t *testing.T) {
+	keyFile := writeKey(t, "EdDSA")
+	s := apptest.New(t, func(cfg *config.Config) {
+		cfg.JWTAlgorithm = "EdDSA"
+		cfg.JWTSigningKeyFile = keyFile
+	})
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	enrollTOTP(t, s.Login(t, adminEmail, adminPassword), adminPassword, time.Now())
+	ch := challenge(t, s.NewClient(t), adminEmail, adminPassword)
+
+	if status := getMe(t, s, ch.MfaToken); status != http.StatusUnauthorized {
+		t.Errorf("MFA challenge token as a bearer token = %d, want 401", status)
+	}
+
+	// Another service that only knows the JWKS and the audience must refuse
+	// it too, without knowing about token_use.
+	set := jwks(t, s)
+	_, err := jwt.Parse(ch.MfaToken, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return publicKey(t, set, kid), nil
+	}, jwt.WithIssuer(s.Config.JWTIssuer), jwt.WithAudience(s.Config.JWTAudience))
+	if !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Errorf("verifying the challenge token for the access audience = %v, want an audience error", err)
+	}
+}
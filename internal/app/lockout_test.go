@@ -0,0 +1,163 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// loginProblem logs in and returns the problem it is rejected with.
+func loginProblem(t *testing.T, s *apptest.Server, email, password string) *client.APIError {
+	t.Helper()
+	_, err := s.NewClient(t).Login(context.Background(), email, password)
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Login as %s = %v, want an API error", email, err)
+	}
+	return apiErr
+}
+
+// sameAnswer reports whether two rejections would look alike to a client.
+func sameAnswer(a, b *client.APIError) bool {
+	return a.StatusCode == b.StatusCode && a.Type == b.Type && a.Title == b.Title &&
+		(a.Detail == nil) == (b.Detail == nil) && (a.Detail == nil || *a.Detail == *b.Detail)
+}
+
+func TestLoginErrorsDoNotRevealAccounts(t *testing.T) {
+	s := newServer(t)
+
+	wrongPassword := loginProblem(t, s, adminEmail, "Wrong-Passw0rd")
+	if wrongPassword.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("wrong password = %d, want 401", wrongPassword.StatusCode)
+	}
+	unknownEmail := loginProblem(t, s, "nobody@example.com", "Wrong-Passw0rd")
+	if !sameAnswer(unknownEmail, wrongPassword) {
+		t.Errorf("unknown email answered %v, wrong password %v; want the same", unknownEmail, wrongPassword)
+	}
+}
+
+func TestAccountLockout(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.LoginMaxFailures = 3 })
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "locked@example.com", "User-Passw0rd")
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	var wrongPassword *client.APIError
+	for range 3 {
+		wrongPassword = loginProblem(t, s, user.Email, "Wrong-Passw0rd")
+	}
+
+	// Locked out, the right password is refused exactly like a wrong one.
+	locked := loginProblem(t, s, user.Email, "User-Passw0rd")
+	if !sameAnswer(locked, wrongPassword) {
+		t.Fatalf("locked account answered %v, wrong password %v; want the same", locked, wrongPassword)
+	}
+
+	resp, err := admin.Raw().UnlockUserWithResponse(context.Background(), int(user.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() >= 300 {
+		t.Fatalf("UnlockUser = %d", resp.StatusCode())
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+}
+
+func TestConcurrentFailuresLockAccount(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) {
+		cfg.LoginMaxFailures = 5
+		cfg.BcryptCost = 10
+	})
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	// Hashing slowly enough, guesses sent in parallel all read the count before
+	// any is recorded; the lock must still follow from the count they add up to.
+	var wg sync.WaitGroup
+	for range 40 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loginProblem(t, s, user.Email, "Wrong-Passw0rd")
+		}()
+	}
+	wg.Wait()
+
+	var stored model.User
+	if err := s.DB.First(&stored, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.LockedUntil == nil {
+		t.Fatalf("after 40 parallel failures failed_logins = %d and the account is not locked", stored.FailedLogins)
+	}
+	if status := loginProblem(t, s, user.Email, "User-Passw0rd").StatusCode; status != http.StatusUnauthorized {
+		t.Errorf("right password on the locked account = %d, want 401", status)
+	}
+}
+
+func TestSuccessfulLoginResetsFailures(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.LoginMaxFailures = 3 })
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	for range 2 {
+		loginProblem(t, s, user.Email, "Wrong-Passw0rd")
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+	for range 2 {
+		loginProblem(t, s, user.Email, "Wrong-Passw0rd")
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+}
+
+func TestLoginThrottledByIP(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.LoginIPMaxFailures = 2 })
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+
+	loginProblem(t, s, "one@example.com", "Wrong-Passw0rd")
+	loginProblem(t, s, "two@example.com", "Wrong-Passw0rd")
+
+	// Even the right password waits out the block, so guesses cannot be
+	// spread over many accounts.
+	throttled := loginProblem(t, s, adminEmail, adminPassword)
+	if throttled.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("login after IP failures = %d, want 429", throttled.StatusCode)
+	}
+	if throttled.RetryAfter == nil || *throttled.RetryAfter <= 0 {
+		t.Errorf("retry_after = %v, want the seconds to wait", throttled.RetryAfter)
+	}
+}
+
+func TestParallelLoginsThrottledByIP(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) {
+		cfg.LoginIPMaxFailures = 3
+		cfg.BcryptCost = 10
+	})
+
+	// Guesses in flight count against the IP, so no more than the threshold
+	// get to check a password however many arrive at once.
+	statuses := make([]int, 20)
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = loginProblem(t, s, "nobody@example.com", "Wrong-Passw0rd").StatusCode
+		}()
+	}
+	wg.Wait()
+
+	checked := 0
+	for _, status := range statuses {
+		if status != http.StatusTooManyRequests {
+			checked++
+		}
+	}
+	if checked > 3 {
+		t.Errorf("%d of 20 parallel guesses were checked, want at most 3", checked)
+	}
+}
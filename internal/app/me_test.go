@@ -0,0 +1,81 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func patchMe(t *testing.T, c *client.Client, contentType, body string) *client.PatchMeResponse {
+	t.Helper()
+	resp, err := c.Raw().PatchMeWithBodyWithResponse(context.Background(), nil, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestMeShowsAndEditsOwnProfile(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	viewer := s.CreateUser(t, "viewer", "viewer@example.com", "Viewer-Passw0rd")
+	c := s.Login(t, viewer.Email, "Viewer-Passw0rd")
+
+	// A viewer only sees names of others, but all of their own record.
+	me, err := c.Me(ctx)
+	if err != nil {
+		t.Fatalf("Me: %v", err)
+	}
+	if me.Id != int(viewer.ID) || me.Email != viewer.Email || me.Phone != viewer.Phone {
+		t.Errorf("Me = %+v, want the viewer's full record", me)
+	}
+
+	resp := patchMe(t, c, "application/merge-patch+json", `{"name":"Renamed Viewer","phone":"+14155550199"}`)
+	if resp.JSON200 == nil || resp.JSON200.Name != "Renamed Viewer" || resp.JSON200.Phone != "+14155550199" {
+		t.Fatalf("PatchMe = %d, want 200 with the new name and phone: %s", resp.StatusCode(), resp.Body)
+	}
+
+	// Nobody grants themselves a role or a new email this way.
+	resp = patchMe(t, c, "application/json-patch+json", `[{"op":"add","path":"/role","value":"admin"}]`)
+	if resp.StatusCode() != http.StatusForbidden {
+		t.Errorf("PatchMe of the role = %d, want 403: %s", resp.StatusCode(), resp.Body)
+	}
+	if me, _ := c.Me(ctx); me.Role != "viewer" {
+		t.Errorf("role after PatchMe = %s", me.Role)
+	}
+
+	admin, err := s.Login(t, adminEmail, adminPassword).Me(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = patchMe(t, c, "application/merge-patch+json", `{"phone":"`+admin.Phone+`"}`)
+	if resp.StatusCode() != http.StatusConflict || resp.ApplicationproblemJSON409 == nil || deref(resp.ApplicationproblemJSON409.Field) != "phone" {
+		t.Errorf("PatchMe to a taken phone = %d, want 409 on phone", resp.StatusCode())
+	}
+}
+
+func TestChangeMyPasswordNeedsOldPassword(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	c := s.Login(t, user.Email, "User-Passw0rd")
+
+	resp, err := c.Raw().ChangeMyPasswordWithResponse(ctx, client.ChangePasswordRequest{OldPassword: "Wrong-Passw0rd", NewPassword: "New-Passw0rd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusForbidden {
+		t.Errorf("ChangeMyPassword with a wrong old password = %d, want 403", resp.StatusCode())
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
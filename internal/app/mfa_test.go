@@ -0,0 +1,182 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+// enrollTOTP turns on two-factor authentication for the signed-in c with a
+// code for now, and returns the secret and recovery codes.
+func enrollTOTP(t *testing.T, c *client.Client, password string, now time.Time) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrolled, err := c.Raw().EnrollTOTPWithResponse(ctx, client.TOTPEnrollRequest{Password: password})
+	if err != nil || enrolled.JSON200 == nil {
+		t.Fatalf("EnrollTOTP: %v", err)
+	}
+	secret := enrolled.JSON200.Secret
+
+	confirmed, err := c.Raw().ConfirmTOTPWithResponse(ctx, client.MFACodeRequest{Code: code(t, secret, now)})
+	if err != nil || confirmed.JSON200 == nil {
+		t.Fatalf("ConfirmTOTP: %v", err)
+	}
+	if len(confirmed.JSON200.RecoveryCodes) == 0 {
+		t.Fatal("ConfirmTOTP returned no recovery codes")
+	}
+	return secret, confirmed.JSON200.RecoveryCodes
+}
+
+func code(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	c, err := totp.GenerateCode(secret, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// challenge logs in with a password and returns the second-factor challenge.
+func challenge(t *testing.T, c *client.Client, email, password string) *client.MFAChallenge {
+	t.Helper()
+	ch, err := c.Login(context.Background(), email, password)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if ch == nil {
+		t.Fatal("Login issued tokens without a second factor")
+	}
+	if access, _ := c.Tokens(); access != "" {
+		t.Fatal("Login issued tokens alongside the challenge")
+	}
+	return ch
+}
+
+func listUsersStatus(t *testing.T, c *client.Client) int {
+	t.Helper()
+	resp, err := c.Raw().ListUsersWithResponse(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode()
+}
+
+func wantMFAFailure(t *testing.T, err error, what string) {
+	t.Helper()
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("%s = %v, want 401", what, err)
+	}
+}
+
+func TestTOTPRequiredForAdmins(t *testing.T) {
+	s := apptest.New(t, func(cfg *config.Config) { cfg.MFARequiredRoles = []string{"admin"} })
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	ctx := context.Background()
+
+	// Without a second factor an admin can only reach their own account,
+	// to enroll one.
+	passwordOnly := s.Login(t, adminEmail, adminPassword)
+	if status := listUsersStatus(t, passwordOnly); status != http.StatusForbidden {
+		t.Fatalf("ListUsers without TOTP = %d, want 403", status)
+	}
+	if _, err := passwordOnly.Me(ctx); err != nil {
+		t.Fatalf("Me without TOTP: %v", err)
+	}
+
+	now := time.Now()
+	secret, _ := enrollTOTP(t, passwordOnly, adminPassword, now)
+
+	c := s.NewClient(t)
+	ch := challenge(t, c, adminEmail, adminPassword)
+
+	wrong := "000000"
+	if wrong == code(t, secret, now.Add(30*time.Second)) {
+		wrong = "111111"
+	}
+	wantMFAFailure(t, c.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, Code: &wrong}), "wrong code")
+
+	// The enrollment code's step is spent, so sign in with the next one.
+	next := code(t, secret, now.Add(30*time.Second))
+	if err := c.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, Code: &next}); err != nil {
+		t.Fatalf("VerifyMFA: %v", err)
+	}
+	if status := listUsersStatus(t, c); status != http.StatusOK {
+		t.Errorf("ListUsers with TOTP = %d, want 200", status)
+	}
+}
+
+func TestTOTPCodeCannotBeReplayed(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	now := time.Now()
+	secret, _ := enrollTOTP(t, s.Login(t, adminEmail, adminPassword), adminPassword, now)
+
+	next := code(t, secret, now.Add(30*time.Second))
+	first := s.NewClient(t)
+	ch := challenge(t, first, adminEmail, adminPassword)
+	if err := first.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, Code: &next}); err != nil {
+		t.Fatalf("VerifyMFA: %v", err)
+	}
+
+	// A code seen over someone's shoulder is no good once it has been used.
+	second := s.NewClient(t)
+	ch = challenge(t, second, adminEmail, adminPassword)
+	wantMFAFailure(t, second.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, Code: &next}), "replayed code")
+
+	enrollment := code(t, secret, now)
+	wantMFAFailure(t, second.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, Code: &enrollment}), "code older than the last one used")
+}
+
+func TestRecoveryCodeWorksOnce(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	_, recovery := enrollTOTP(t, s.Login(t, adminEmail, adminPassword), adminPassword, time.Now())
+
+	first := s.NewClient(t)
+	ch := challenge(t, first, adminEmail, adminPassword)
+	if err := first.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, RecoveryCode: &recovery[0]}); err != nil {
+		t.Fatalf("VerifyMFA with a recovery code: %v", err)
+	}
+	if _, err := first.Me(ctx); err != nil {
+		t.Fatalf("Me after recovery: %v", err)
+	}
+
+	second := s.NewClient(t)
+	ch = challenge(t, second, adminEmail, adminPassword)
+	wantMFAFailure(t, second.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, RecoveryCode: &recovery[0]}), "reused recovery code")
+	if err := second.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: ch.MfaToken, RecoveryCode: &recovery[1]}); err != nil {
+		t.Errorf("VerifyMFA with another recovery code: %v", err)
+	}
+}
+
+func TestTOTPEnrollmentNeedsPassword(t *testing.T) {
+	s := newServer(t)
+	c := s.Login(t, adminEmail, adminPassword)
+
+	// A stolen session alone must not be able to bind an authenticator.
+	resp, err := c.Raw().EnrollTOTPWithResponse(context.Background(), client.TOTPEnrollRequest{Password: "Wrong-Passw0rd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusForbidden || resp.JSON200 != nil {
+		t.Fatalf("EnrollTOTP with a wrong password = %d, want 403", resp.StatusCode())
+	}
+
+	var stored model.User
+	if err := s.DB.First(&stored, "email = ?", adminEmail).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.TOTPSecret != "" {
+		t.Error("a TOTP secret was stored without the password")
+	}
+}
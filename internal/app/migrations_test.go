@@ -0,0 +1,102 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/internal/db"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func migrator(t *testing.T, s *apptest.Server) *db.Migrator {
+	t.Helper()
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver, err := db.NewDriver(s.Config.DBDriver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := db.NewMigrator(sqlDB, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMigrationsRollBackAndReapply(t *testing.T) {
+	s := apptest.New(t)
+	ctx := context.Background()
+	m := migrator(t, s)
+
+	if current, err := m.CurrentVersion(ctx); err != nil || current != m.LatestVersion() {
+		t.Fatalf("fresh database at version %d (%v), want %d", current, err, m.LatestVersion())
+	}
+
+	n, err := m.Down(ctx, m.LatestVersion())
+	if err != nil || n != m.LatestVersion() {
+		t.Fatalf("Down rolled back %d migrations (%v), want %d", n, err, m.LatestVersion())
+	}
+	var tables []string
+	s.DB.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'schema_migrations'").Scan(&tables)
+	if len(tables) != 0 {
+		t.Errorf("tables left after rolling everything back: %v", tables)
+	}
+
+	if n, err := m.Up(ctx); err != nil || n != m.LatestVersion() {
+		t.Fatalf("Up applied %d migrations (%v), want %d", n, err, m.LatestVersion())
+	}
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	s.Login(t, adminEmail, adminPassword)
+}
+
+func TestNewerSchemaIsRefused(t *testing.T) {
+	s := apptest.New(t)
+	m := migrator(t, s)
+
+	if err := s.DB.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'from_the_future', CURRENT_TIMESTAMP)", m.LatestVersion()+1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CheckCompatible(context.Background()); !errors.Is(err, db.ErrSchemaTooNew) {
+		t.Errorf("CheckCompatible = %v, want ErrSchemaTooNew", err)
+	}
+}
+
+func TestEveryDriverHasEveryMigration(t *testing.T) {
+	dir := filepath.Join("..", "db", "migrations")
+	files := func(driver string) map[string]bool {
+		entries, err := os.ReadDir(filepath.Join(dir, driver))
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := map[string]bool{}
+		for _, e := range entries {
+			names[e.Name()] = true
+		}
+		return names
+	}
+
+	sqlite := files("sqlite")
+	for name := range sqlite {
+		if up, ok := strings.CutSuffix(name, ".up.sql"); ok && !sqlite[up+".down.sql"] {
+			t.Errorf("%s has no down migration", name)
+		}
+	}
+	for _, driver := range []string{"postgres", "mysql"} {
+		other := files(driver)
+		for name := range sqlite {
+			if !other[name] {
+				t.Errorf("%s has no %s", driver, name)
+			}
+		}
+		for name := range other {
+			if !sqlite[name] {
+				t.Errorf("sqlite has no %s, which %s has", name, driver)
+			}
+		}
+	}
+}
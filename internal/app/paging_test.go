@@ -0,0 +1,113 @@
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+)
+
+// seedUsers adds n users named "Member <i>" with ages 20+i, after the admin
+// from newServer.
+func seedUsers(t *testing.T, s *apptest.Server, n int) {
+	t.Helper()
+	for i := range n {
+		u := s.CreateUser(t, "user", fmt.Sprintf("member%d@example.com", i), "User-Passw0rd")
+		if err := s.DB.Model(u).Updates(map[string]any{"name": fmt.Sprintf("Member %d", i), "age": 20 + i}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// collect pages through every user matching params, limit at a time.
+func collect(t *testing.T, c *client.Client, params client.ListUsersParams, limit int) []client.UserFull {
+	t.Helper()
+	params.Limit = &limit
+	var users []client.UserFull
+	for view, err := range c.Users(context.Background(), &params) {
+		if err != nil {
+			t.Fatalf("Users: %v", err)
+		}
+		user, err := view.AsUserFull()
+		if err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, user)
+	}
+	return users
+}
+
+func TestListUsersCursorPaging(t *testing.T) {
+	s := newServer(t)
+	seedUsers(t, s, 7)
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	first, err := admin.ListUsers(context.Background(), &client.ListUsersParams{Limit: ptr(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Items) != 3 || first.NextCursor == nil {
+		t.Fatalf("first page has %d users and cursor %v, want 3 and a cursor", len(first.Items), first.NextCursor)
+	}
+
+	// A user added mid-way turns up once, at the end, without shifting pages.
+	s.CreateUser(t, "user", "late@example.com", "User-Passw0rd")
+	rest := collect(t, admin, client.ListUsersParams{Cursor: first.NextCursor}, 3)
+	if len(rest) != 6 {
+		t.Fatalf("pages after the first hold %d users, want 6", len(rest))
+	}
+	seen := map[int]bool{}
+	for _, view := range first.Items {
+		u, _ := view.AsUserFull()
+		seen[u.Id] = true
+	}
+	last := 0
+	for _, u := range rest {
+		if seen[u.Id] || u.Id < last {
+			t.Errorf("user %d is repeated or out of order", u.Id)
+		}
+		seen[u.Id], last = true, u.Id
+	}
+
+	bad := "not-a-cursor"
+	if status := listUsers(t, admin, &client.ListUsersParams{Cursor: &bad}).StatusCode(); status != http.StatusBadRequest {
+		t.Errorf("ListUsers with a bad cursor = %d, want 400", status)
+	}
+}
+
+func TestListUsersFiltersAndSorts(t *testing.T) {
+	s := newServer(t)
+	seedUsers(t, s, 7)
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	sort := client.MinusAge
+	users := collect(t, admin, client.ListUsersParams{Sort: &sort, MinAge: ptr(22), MaxAge: ptr(25)}, 2)
+	var ages []int
+	for _, u := range users {
+		ages = append(ages, u.Age)
+	}
+	if fmt.Sprint(ages) != "[25 24 23 22]" {
+		t.Errorf("ages 22 to 25 by -age = %v, want [25 24 23 22]", ages)
+	}
+
+	name, role := "MEMBER 1", client.Role("user")
+	if users := collect(t, admin, client.ListUsersParams{Name: &name, Role: &role}, 5); len(users) != 1 || users[0].Name != "Member 1" {
+		t.Errorf("name filter %q found %v, want Member 1 only", name, users)
+	}
+
+	sort = client.Name
+	users = collect(t, admin, client.ListUsersParams{Sort: &sort}, 3)
+	for i := 1; i < len(users); i++ {
+		if users[i-1].Name > users[i].Name {
+			t.Errorf("sorted by name, %q comes before %q", users[i-1].Name, users[i].Name)
+		}
+	}
+
+	if status := listUsers(t, admin, &client.ListUsersParams{Limit: ptr(0)}).StatusCode(); status != http.StatusBadRequest {
+		t.Errorf("ListUsers with limit 0 = %d, want 400", status)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
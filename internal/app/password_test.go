@@ -0,0 +1,57 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// wantValidation checks that err is a 422 naming field.
+func wantValidation(t *testing.T, err error, field, what string) {
+	t.Helper()
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("%s = %v, want 422", what, err)
+	}
+	if apiErr.Errors == nil || len(*apiErr.Errors) != 1 || (*apiErr.Errors)[0].Field != field {
+		t.Errorf("%s failed on %v, want %s", what, apiErr.Errors, field)
+	}
+}
+
+func TestPasswordLongerThan72BytesRejected(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	// bcrypt cannot hash more than 72 bytes; that is the client's mistake, not
+	// a server error.
+	longest := "Aa1" + strings.Repeat("x", 69)
+	tooLong := longest + "x"
+
+	_, err := admin.CreateUser(ctx, client.CreateUserRequest{Name: "Long", Email: "long@example.com", Phone: "+14155550100", Role: "user", Password: tooLong})
+	wantValidation(t, err, "password", "CreateUser with a 73-byte password")
+
+	created, err := admin.CreateUser(ctx, client.CreateUserRequest{Name: "Long", Email: "long@example.com", Phone: "+14155550100", Role: "user", Password: longest})
+	if err != nil {
+		t.Fatalf("CreateUser with a 72-byte password: %v", err)
+	}
+	user := s.Login(t, "long@example.com", longest)
+
+	_, err = admin.UpdateUser(ctx, created.Id, client.UpdateUserRequest{Name: "Long", Email: "long@example.com", Phone: "+14155550100", Role: "user", Password: &tooLong}, "")
+	wantValidation(t, err, "password", "UpdateUser with a 73-byte password")
+
+	change, err := user.Raw().ChangeMyPasswordWithResponse(ctx, client.ChangePasswordRequest{OldPassword: longest, NewPassword: tooLong})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.StatusCode() != http.StatusUnprocessableEntity {
+		t.Errorf("ChangeMyPassword to a 73-byte password = %d, want 422", change.StatusCode())
+	}
+
+	if resp := resetPassword(t, s, "any-token", tooLong); resp.StatusCode() != http.StatusUnprocessableEntity {
+		t.Errorf("ResetPassword to a 73-byte password = %d, want 422", resp.StatusCode())
+	}
+}
@@ -0,0 +1,73 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func patchUser(t *testing.T, c *client.Client, id int, contentType, body string) *client.PatchUserResponse {
+	t.Helper()
+	resp, err := c.Raw().PatchUserWithBodyWithResponse(context.Background(), id, nil, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestMergePatchChangesOnlyGivenFields(t *testing.T) {
+	s := newServer(t)
+	admin := s.Login(t, adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	resp := patchUser(t, admin, int(user.ID), "application/merge-patch+json", `{"name":"Merged","age":41}`)
+	if resp.JSON200 == nil {
+		t.Fatalf("merge patch = %d, want 200: %s", resp.StatusCode(), resp.Body)
+	}
+	got := resp.JSON200
+	if got.Name != "Merged" || got.Age != 41 || got.Email != user.Email || got.Phone != user.Phone || string(got.Role) != user.Role {
+		t.Errorf("after merge patch the user is %+v", got)
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+}
+
+func TestJSONPatchAppliesAllOrNothing(t *testing.T) {
+	s := newServer(t)
+	admin := s.Login(t, adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	id := int(user.ID)
+
+	// A failed test op stops the whole patch.
+	resp := patchUser(t, admin, id, "application/json-patch+json",
+		`[{"op":"replace","path":"/name","value":"Too Early"},{"op":"test","path":"/email","value":"other@example.com"}]`)
+	if resp.StatusCode() != http.StatusBadRequest {
+		t.Fatalf("patch with a failing test = %d, want 400", resp.StatusCode())
+	}
+	view, _, err := admin.GetUser(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := view.AsUserFull(); current.Name != user.Name {
+		t.Errorf("name after a failed patch = %q, want %q", current.Name, user.Name)
+	}
+
+	resp = patchUser(t, admin, id, "application/json-patch+json",
+		`[{"op":"test","path":"/email","value":"user@example.com"},{"op":"replace","path":"/name","value":"Patched"},{"op":"copy","from":"/name","path":"/name"}]`)
+	if resp.JSON200 == nil || resp.JSON200.Name != "Patched" {
+		t.Fatalf("JSON patch = %d, want 200 with the new name: %s", resp.StatusCode(), resp.Body)
+	}
+
+	// The patched document is validated like any other write.
+	resp = patchUser(t, admin, id, "application/json-patch+json", `[{"op":"remove","path":"/email"}]`)
+	if resp.StatusCode() != http.StatusUnprocessableEntity {
+		t.Errorf("patch removing the email = %d, want 422", resp.StatusCode())
+	}
+	// The password is not part of the document and cannot be patched.
+	resp = patchUser(t, admin, id, "application/json-patch+json", `[{"op":"replace","path":"/password","value":"Other-Passw0rd"}]`)
+	if resp.StatusCode() != http.StatusBadRequest {
+		t.Errorf("patch of the password = %d, want 400", resp.StatusCode())
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+}
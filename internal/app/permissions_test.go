@@ -0,0 +1,100 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+)
+
+func TestRolePermissions(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	target := s.CreateUser(t, "user", "target@example.com", "User-Passw0rd")
+	id := int(target.ID)
+
+	// Each call needs one permission; the admin has all of them.
+	calls := []struct {
+		name       string
+		permission client.Permission
+		call       func(c *client.ClientWithResponses) (int, error)
+	}{
+		{"ListUsers", client.UsersRead, func(c *client.ClientWithResponses) (int, error) {
+			r, err := c.ListUsersWithResponse(ctx, nil)
+			return status(r, err)
+		}},
+		{"CreateUser", client.UsersWrite, func(c *client.ClientWithResponses) (int, error) {
+			r, err := c.CreateUserWithResponse(ctx, nil, client.CreateUserRequest{Name: "Created", Email: "created@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"})
+			return status(r, err)
+		}},
+		{"DeleteUser", client.UsersDelete, func(c *client.ClientWithResponses) (int, error) {
+			r, err := c.DeleteUserWithResponse(ctx, id+1000, nil)
+			return status(r, err)
+		}},
+		{"RestoreUser", client.UsersRestore, func(c *client.ClientWithResponses) (int, error) {
+			r, err := c.RestoreUserWithResponse(ctx, id)
+			return status(r, err)
+		}},
+		{"ListAuditLogs", client.AuditRead, func(c *client.ClientWithResponses) (int, error) {
+			r, err := c.ListAuditLogsWithResponse(ctx, nil)
+			return status(r, err)
+		}},
+		{"ListUserTokens", client.TokensManage, func(c *client.ClientWithResponses) (int, error) {
+			r, err := c.ListUserTokensWithResponse(ctx, id)
+			return status(r, err)
+		}},
+	}
+	granted := map[string]map[client.Permission]bool{
+		"admin":  {client.UsersRead: true, client.UsersWrite: true, client.UsersDelete: true, client.UsersRestore: true, client.AuditRead: true, client.TokensManage: true},
+		"user":   {client.UsersRead: true},
+		"viewer": {client.UsersRead: true},
+	}
+
+	for role, perms := range granted {
+		c := loginAs(t, s, role)
+		for _, call := range calls {
+			got, err := call.call(c.Raw())
+			if err != nil {
+				t.Fatalf("%s as %s: %v", call.name, role, err)
+			}
+			if perms[call.permission] == (got == http.StatusForbidden) {
+				t.Errorf("%s as %s = %d, but %s is granted: %v", call.name, role, got, call.permission, perms[call.permission])
+			}
+		}
+	}
+}
+
+// loginAs signs in as a fresh user with role, or as the admin.
+func loginAs(t *testing.T, s *apptest.Server, role string) *client.Client {
+	t.Helper()
+	if role == "admin" {
+		return s.Login(t, adminEmail, adminPassword)
+	}
+	email := role + "-caller@example.com"
+	s.CreateUser(t, role, email, "Caller-Passw0rd")
+	return s.Login(t, email, "Caller-Passw0rd")
+}
+
+func status(resp interface{ StatusCode() int }, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	return resp.StatusCode(), nil
+}
+
+func TestForbiddenNamesMissingPermission(t *testing.T) {
+	s := newServer(t)
+	viewer := loginAs(t, s, "viewer")
+
+	resp, err := viewer.Raw().ListAuditLogsWithResponse(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusForbidden || resp.ApplicationproblemJSON403 == nil {
+		t.Fatalf("ListAuditLogs as a viewer = %d, want a 403 problem", resp.StatusCode())
+	}
+	if p := resp.ApplicationproblemJSON403.Permission; p == nil || *p != client.AuditRead {
+		t.Errorf("403 names permission %v, want audit:read", p)
+	}
+}
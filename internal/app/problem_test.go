@@ -0,0 +1,61 @@
+package app_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrorsAreProblemDetails(t *testing.T) {
+	s := newServer(t)
+	access, _ := s.Login(t, adminEmail, adminPassword).Tokens()
+	s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	userAccess, _ := s.Login(t, "user@example.com", "User-Passw0rd").Tokens()
+	taken := func(email, phone string) string {
+		return `{"name":"Taken","email":"` + email + `","phone":"` + phone + `","role":"user","password":"User-Passw0rd"}`
+	}
+	if resp, _ := send(t, s, access, "POST", "/users", taken("first@example.com", "+14155550100")); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("CreateUser = %d, want 201", resp.StatusCode)
+	}
+
+	instances := map[string]bool{}
+	for _, tc := range []struct {
+		name, access, method, path, body string
+		status                           int
+		typ, title                       string
+		member, value                    string
+	}{
+		{"missing user", access, "GET", "/users/9999", "", http.StatusNotFound, "/problems/not-found", "Resource not found", "resource", "user"},
+		{"unknown route", access, "GET", "/nope", "", http.StatusNotFound, "/problems/not-found", "Resource not found", "", ""},
+		{"email taken", access, "POST", "/users", taken("first@example.com", "+14155550101"), http.StatusConflict, "/problems/conflict", "Resource conflict", "field", "email"},
+		{"phone taken", access, "POST", "/users", taken("second@example.com", "+14155550100"), http.StatusConflict, "/problems/conflict", "Resource conflict", "field", "phone"},
+		{"invalid fields", access, "POST", "/users", taken("third@example.com", "12345"), http.StatusUnprocessableEntity, "/problems/validation-failed", "Validation failed", "", ""},
+		{"no token", "", "GET", "/users", "", http.StatusUnauthorized, "/problems/unauthorized", "Authentication required", "", ""},
+		{"no permission", userAccess, "DELETE", "/users/1", "", http.StatusForbidden, "/problems/forbidden", "Permission denied", "", ""},
+	} {
+		resp, p := send(t, s, tc.access, tc.method, tc.path, tc.body)
+		if resp.StatusCode != tc.status || p.Status != tc.status {
+			t.Errorf("%s: status %d, body status %d, want %d", tc.name, resp.StatusCode, p.Status, tc.status)
+		}
+		if p.Type != tc.typ || p.Title != tc.title || p.Detail == "" {
+			t.Errorf("%s: type %q title %q detail %q, want %q %q and a detail", tc.name, p.Type, p.Title, p.Detail, tc.typ, tc.title)
+		}
+		if !strings.HasPrefix(p.Instance, "urn:uuid:") || instances[p.Instance] {
+			t.Errorf("%s: instance %q, want the request's own urn:uuid", tc.name, p.Instance)
+		}
+		instances[p.Instance] = true
+		switch tc.member {
+		case "resource":
+			if p.Resource != tc.value {
+				t.Errorf("%s: resource %q, want %q", tc.name, p.Resource, tc.value)
+			}
+		case "field":
+			if p.Field != tc.value {
+				t.Errorf("%s: field %q, want %q", tc.name, p.Field, tc.value)
+			}
+		}
+		if tc.status == http.StatusUnprocessableEntity && len(p.Errors) != 1 || len(p.Errors) == 1 && p.Errors[0].Field != "phone" {
+			t.Errorf("%s: errors %v, want the phone only", tc.name, p.Errors)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDeletedUserCanBeRestored(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	id := int(user.ID)
+
+	if err := admin.DeleteUser(ctx, id, ""); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	var apiErr *client.APIError
+	if _, _, err := admin.GetUser(ctx, id); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetUser after delete = %v, want 404", err)
+	}
+	if status := loginProblem(t, s, user.Email, "User-Passw0rd").StatusCode; status != http.StatusUnauthorized {
+		t.Errorf("Login after delete = %d, want 401", status)
+	}
+
+	withDeleted := true
+	page, err := admin.ListUsers(ctx, &client.ListUsersParams{IncludeDeleted: &withDeleted})
+	if err != nil {
+		t.Fatalf("ListUsers with deleted: %v", err)
+	}
+	if len(page.Items) != 2 {
+		t.Errorf("ListUsers with deleted returned %d users, want 2", len(page.Items))
+	}
+
+	if _, err := admin.RestoreUser(ctx, id); err != nil {
+		t.Fatalf("RestoreUser: %v", err)
+	}
+	if _, err := admin.RestoreUser(ctx, id); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("restoring a live user = %v, want 409", err)
+	}
+	s.Login(t, user.Email, "User-Passw0rd")
+}
+
+func TestPurgeRemovesUserRows(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+	user := s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+
+	// Give the user a refresh token, recovery codes and an API token.
+	c := s.Login(t, user.Email, "User-Passw0rd")
+	enrollTOTP(t, c, "User-Passw0rd", time.Now())
+	created, err := c.Raw().CreateMyTokenWithResponse(ctx, client.CreateAPITokenRequest{Name: "ci", Scopes: []client.Permission{client.UsersRead}})
+	if err != nil || created.JSON201 == nil {
+		t.Fatalf("CreateMyToken: %v", err)
+	}
+
+	if err := admin.DeleteUser(ctx, int(user.ID), ""); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	// Still within retention.
+	if n, err := s.Users.PurgeDeleted(ctx, time.Hour); err != nil || n != 0 {
+		t.Fatalf("PurgeDeleted within retention = %d, %v, want 0", n, err)
+	}
+	if n, err := s.Users.PurgeDeleted(ctx, 0); err != nil || n != 1 {
+		t.Fatalf("PurgeDeleted = %d, %v, want 1", n, err)
+	}
+
+	var users int64
+	if err := s.DB.Unscoped().Model(&model.User{}).Where("id = ?", user.ID).Count(&users).Error; err != nil || users != 0 {
+		t.Errorf("purged user still stored: %d rows, %v", users, err)
+	}
+	for _, owned := range []any{&model.RefreshToken{}, &model.RecoveryCode{}, &model.APIToken{}} {
+		var n int64
+		if err := s.DB.Model(owned).Where("user_id = ?", user.ID).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 {
+			t.Errorf("%d %T rows of the purged user remain", n, owned)
+		}
+	}
+	if _, err := admin.RestoreUser(ctx, int(user.ID)); err == nil {
+		t.Error("RestoreUser succeeded after purge")
+	}
+}
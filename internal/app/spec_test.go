@@ -0,0 +1,97 @@
+package app_test
+
+import (
+	"encoding/json"
+	"go-crud-oapi/internal/apptest"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// problemBody is the RFC 7807 body of an error response.
+type problemBody struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+	Field    string `json:"field"`
+	Resource string `json:"resource"`
+	Errors   []struct {
+		Field string `json:"field"`
+		Rule  string `json:"rule"`
+	} `json:"errors"`
+}
+
+// send makes a request the typed client could not, signed in with access if
+// it is not empty, and decodes the problem it answers with.
+func send(t *testing.T, s *apptest.Server, access, method, path, body string) (*http.Response, problemBody) {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if access != "" {
+		req.Header.Set("Authorization", "Bearer "+access)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p problemBody
+	if resp.StatusCode >= http.StatusBadRequest {
+		if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+			t.Errorf("%s %s answered %d as %q, want application/problem+json", method, path, resp.StatusCode, ct)
+		}
+		if err := json.Unmarshal(raw, &p); err != nil {
+			t.Fatalf("%s %s: %v in %s", method, path, err, raw)
+		}
+	}
+	return resp, p
+}
+
+func TestRequestsAreCheckedAgainstSpec(t *testing.T) {
+	s := newServer(t)
+	access, _ := s.Login(t, adminEmail, adminPassword).Tokens()
+
+	for _, tc := range []struct {
+		method, path, body string
+		fields             []string
+	}{
+		{"GET", "/users?limit=abc", "", []string{"limit"}},
+		{"GET", "/users?sort=password", "", []string{"sort"}},
+		{"GET", "/users/abc", "", []string{"id"}},
+		{"POST", "/users", `{"name":"Al"}`, []string{"name", "email", "phone", "role", "password"}},
+		{"POST", "/users", `{"name":"Alice","email":"a@example.com","phone":"+14155550100","role":"root","password":"User-Passw0rd"}`, []string{"role"}},
+		{"POST", "/users", `not json`, []string{"body"}},
+	} {
+		resp, p := send(t, s, access, tc.method, tc.path, tc.body)
+		if resp.StatusCode != http.StatusBadRequest || p.Type != "/problems/invalid-request" {
+			t.Errorf("%s %s %s = %d %s, want an invalid-request 400", tc.method, tc.path, tc.body, resp.StatusCode, p.Type)
+			continue
+		}
+		var fields []string
+		for _, e := range p.Errors {
+			fields = append(fields, e.Field)
+		}
+		if strings.Join(fields, ",") != strings.Join(tc.fields, ",") {
+			t.Errorf("%s %s %s failed on %v, want %v", tc.method, tc.path, tc.body, fields, tc.fields)
+		}
+	}
+
+	// Nothing that failed the spec reached the database.
+	var n int64
+	s.DB.Table("users").Count(&n)
+	if n != 1 {
+		t.Errorf("%d users stored, want only the admin", n)
+	}
+}
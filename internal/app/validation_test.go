@@ -0,0 +1,47 @@
+package app_test
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"sort"
+	"testing"
+)
+
+func TestUserWritesRunValidateTags(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	// Both rules are enforced by the model, not the spec, and every failing
+	// field is reported at once.
+	resp, err := admin.Raw().CreateUserWithResponse(ctx, nil, client.CreateUserRequest{Name: "Invalid", Email: "invalid@example.com", Phone: "12345", Role: "user", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusUnprocessableEntity || resp.ApplicationproblemJSON422 == nil {
+		t.Fatalf("CreateUser with a bad phone and password = %d, want 422", resp.StatusCode())
+	}
+	var fields []string
+	for _, e := range resp.ApplicationproblemJSON422.Errors {
+		fields = append(fields, e.Field)
+	}
+	sort.Strings(fields)
+	if len(fields) != 2 || fields[0] != "password" || fields[1] != "phone" {
+		t.Errorf("CreateUser failed on %v, want password and phone", fields)
+	}
+	var n int64
+	s.DB.Model(&model.User{}).Where("email = ?", "invalid@example.com").Count(&n)
+	if n != 0 {
+		t.Error("an invalid user was stored")
+	}
+
+	user, err := admin.CreateUser(ctx, client.CreateUserRequest{Name: "Valid", Email: "valid@example.com", Phone: "+14155550100", Role: "user", Password: "User-Passw0rd"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	phone := "0800 123"
+	_, err = admin.PatchUser(ctx, user.Id, client.UserMergePatch{Phone: &phone}, "")
+	wantValidation(t, err, "phone", "PatchUser with a bad phone")
+}
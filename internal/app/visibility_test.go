@@ -0,0 +1,117 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"testing"
+)
+
+func listUsers(t *testing.T, c *client.Client, params *client.ListUsersParams) *client.ListUsersResponse {
+	t.Helper()
+	resp, err := c.Raw().ListUsersWithResponse(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestViewersSeeUserSummaries(t *testing.T) {
+	s := newServer(t)
+	me := s.CreateUser(t, "viewer", "viewer@example.com", "Viewer-Passw0rd")
+	viewer := s.Login(t, "viewer@example.com", "Viewer-Passw0rd")
+
+	resp := listUsers(t, viewer, nil)
+	if resp.StatusCode() != http.StatusOK {
+		t.Fatalf("ListUsers as a viewer = %d, want 200", resp.StatusCode())
+	}
+	var page struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal(resp.Body, &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 {
+		t.Fatalf("ListUsers returned %d users, want 2", len(page.Items))
+	}
+	for _, item := range page.Items {
+		if item["id"] == float64(me.ID) {
+			continue // their own record is shown in full
+		}
+		for _, private := range []string{"email", "phone", "age", "role"} {
+			if _, ok := item[private]; ok {
+				t.Errorf("viewer sees %s of user %v", private, item["id"])
+			}
+		}
+	}
+}
+
+func TestViewersCannotQueryPrivateFields(t *testing.T) {
+	s := newServer(t)
+	s.CreateUser(t, "viewer", "viewer@example.com", "Viewer-Passw0rd")
+	viewer := s.Login(t, "viewer@example.com", "Viewer-Passw0rd")
+	admin := s.Login(t, adminEmail, adminPassword)
+
+	role := client.Role("admin")
+	email := "admin"
+	age := 30
+	sortEmail := client.ListUsersParamsSort("email")
+	sortAge := client.ListUsersParamsSort("-age")
+
+	// Each would reveal a hidden field one query at a time.
+	for name, params := range map[string]*client.ListUsersParams{
+		"role":       {Role: &role},
+		"email":      {Email: &email},
+		"min_age":    {MinAge: &age},
+		"max_age":    {MaxAge: &age},
+		"sort=email": {Sort: &sortEmail},
+		"sort=-age":  {Sort: &sortAge},
+	} {
+		if status := listUsers(t, viewer, params).StatusCode(); status != http.StatusForbidden {
+			t.Errorf("viewer filtering by %s = %d, want 403", name, status)
+		}
+		if status := listUsers(t, admin, params).StatusCode(); status != http.StatusOK {
+			t.Errorf("admin filtering by %s = %d, want 200", name, status)
+		}
+	}
+}
+
+func TestReadsNeedAuthentication(t *testing.T) {
+	s := newServer(t)
+	anonymous := s.NewClient(t)
+
+	if status := listUsers(t, anonymous, nil).StatusCode(); status != http.StatusUnauthorized {
+		t.Errorf("ListUsers without a token = %d, want 401", status)
+	}
+	resp, err := anonymous.Raw().GetUserWithResponse(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode() != http.StatusUnauthorized {
+		t.Errorf("GetUser without a token = %d, want 401", resp.StatusCode())
+	}
+}
+
+func TestUsersSeePublicFields(t *testing.T) {
+	s := newServer(t)
+	s.CreateUser(t, "user", "user@example.com", "User-Passw0rd")
+	user := s.Login(t, "user@example.com", "User-Passw0rd")
+
+	resp, err := user.Raw().GetUserWithResponse(context.Background(), 1, nil)
+	if err != nil || resp.StatusCode() != http.StatusOK {
+		t.Fatalf("GetUser of the admin as a user = %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(resp.Body, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["role"] != "admin" || fields["name"] == nil {
+		t.Errorf("user sees %v, want the name and role", fields)
+	}
+	for _, private := range []string{"email", "phone", "age"} {
+		if _, ok := fields[private]; ok {
+			t.Errorf("user sees the admin's %s", private)
+		}
+	}
+}
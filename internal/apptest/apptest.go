@@ -0,0 +1,182 @@
+// Package apptest runs the user service over HTTP on a fresh in-memory
+// SQLite database, for tests that exercise it end to end.
+package apptest
+
+import (
+	"context"
+	"fmt"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/app"
+	"go-crud-oapi/internal/db"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/client"
+	"go-crud-oapi/pkg/mailer"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Server is a running user service. Its handler, database, user service and
+// outgoing mail are exposed so that tests can put it behind a proxy, set up
+// state, run background jobs and read links from emails.
+type Server struct {
+	*httptest.Server
+	Config  *config.Config
+	Handler http.Handler
+	DB      *gorm.DB
+	Mail    *mailer.MemoryMailer
+	Hasher  *auth.PasswordHasher
+	Users   service.UserServiceInterFace
+
+	phones atomic.Int64
+}
+
+// Config returns the configuration New starts from: the production defaults,
+// with cheap password hashing and no second factor required.
+func Config() *config.Config {
+	return &config.Config{
+		DBDriver:      "sqlite",
+		DBName:        ":memory:",
+		DBAutoMigrate: true,
+		Environment:   "test",
+
+		JWTAlgorithm:    "HS256",
+		JWTSecret:       "apptest-secret",
+		JWTIssuer:       "apptest",
+		JWTAudience:     "apptest-api",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: time.Hour,
+
+		PasswordAlgorithm: "bcrypt",
+		BcryptCost:        bcrypt.MinCost,
+
+		SoftDeleteRetention: 30 * 24 * time.Hour,
+		PurgeInterval:       time.Hour,
+
+		OpenAPIValidateResponses: true,
+
+		IdempotencyTTL:   time.Hour,
+		IdempotencyLease: time.Minute,
+
+		LoginMaxFailures:   5,
+		LoginIPMaxFailures: 1000,
+		LoginLockoutBase:   time.Minute,
+		LoginLockoutMax:    time.Hour,
+
+		PasswordResetIPLimit:     20,
+		PasswordResetEmailLimit:  3,
+		PasswordResetLimitWindow: time.Hour,
+
+		MFAIssuer:       "apptest",
+		MFAChallengeTTL: 5 * time.Minute,
+
+		PasswordResetTTL: time.Hour,
+		VerifyEmailTTL:   time.Hour,
+		Mailer:           "memory",
+		MailFrom:         "no-reply@localhost",
+	}
+}
+
+// New starts a server, applying configure to Config() first. It is shut down
+// when the test ends.
+func New(t testing.TB, configure ...func(*config.Config)) *Server {
+	t.Helper()
+
+	cfg := Config()
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	srv := httptest.NewUnstartedServer(nil)
+	cfg.AppBaseURL = "http://" + srv.Listener.Addr().String()
+
+	dbConn := db.Init(cfg)
+	mail := mailer.NewMemoryMailer()
+	a, err := app.New(cfg, dbConn, mail)
+	if err != nil {
+		t.Fatalf("apptest: %v", err)
+	}
+
+	srv.Config.Handler = a.Handler
+	srv.Start()
+	t.Cleanup(func() {
+		srv.Close()
+		if sqlDB, err := dbConn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return &Server{Server: srv, Config: cfg, Handler: a.Handler, DB: dbConn, Mail: mail, Hasher: a.Hasher, Users: a.Users}
+}
+
+// CreateUser inserts a user with role and password straight into the
+// database, bypassing the API and its audit log.
+func (s *Server) CreateUser(t testing.TB, role, email, password string) *model.User {
+	t.Helper()
+
+	hash, err := s.Hasher.Hash(password)
+	if err != nil {
+		t.Fatalf("apptest: hash password: %v", err)
+	}
+	user := &model.User{
+		Name:     "Test " + role,
+		Email:    email,
+		Phone:    fmt.Sprintf("+1415555%04d", s.phones.Add(1)),
+		Role:     role,
+		Password: hash,
+	}
+	if err := s.DB.Create(user).Error; err != nil {
+		t.Fatalf("apptest: create user %s: %v", email, err)
+	}
+	return user
+}
+
+// NewClient returns a client for the server that is not signed in. Retries
+// back off for milliseconds rather than the default hundreds.
+func (s *Server) NewClient(t testing.TB, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	opts = append([]client.Option{client.WithHTTPDoer(s.Client()), client.WithRetries(3, time.Millisecond)}, opts...)
+	c, err := client.New(s.URL, opts...)
+	if err != nil {
+		t.Fatalf("apptest: new client: %v", err)
+	}
+	return c
+}
+
+// Login returns a client signed in as email, which must not have two-factor
+// authentication enabled.
+func (s *Server) Login(t testing.TB, email, password string, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	c := s.NewClient(t, opts...)
+	challenge, err := c.Login(context.Background(), email, password)
+	if err != nil {
+		t.Fatalf("apptest: login as %s: %v", email, err)
+	}
+	if challenge != nil {
+		t.Fatalf("apptest: login as %s: second factor required", email)
+	}
+	return c
+}
+
+// WaitForMail returns the most recent message sent to address once there is
+// one, for emails the server sends after answering.
+func (s *Server) WaitForMail(t testing.TB, address string) mailer.Message {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if msg, ok := s.Mail.Last(address); ok {
+			return msg
+		}
+	}
+	t.Fatalf("apptest: no email sent to %s", address)
+	return mailer.Message{}
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"encoding/json"
+	"go-crud-oapi/internal/handler"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+type AccountController struct {
+	svc   service.AccountServiceInterface
+	users service.UserServiceInterFace
+}
+
+func NewAccountController(svc service.AccountServiceInterface, users service.UserServiceInterFace) *AccountController {
+	return &AccountController{svc: svc, users: users}
+}
+
+// ForgotPassword emails a reset link. It answers 202 whether or not the email
+// is registered.
+func (c *AccountController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("ForgotPassword handler invoked")
+
+	var req model.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	if err := c.svc.ForgotPassword(r.Context(), req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword sets a new password with a token from the reset email.
+func (c *AccountController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("ResetPassword handler invoked")
+
+	var req model.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	if err := c.svc.ResetPassword(r.Context(), req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("Password reset successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail confirms an email address from the link in the verification email.
+func (c *AccountController) VerifyEmail(w http.ResponseWriter, r *http.Request, query handler.VerifyEmailParams) {
+	log := logger.L(r.Context())
+	log.Info("VerifyEmail handler invoked")
+
+	if err := c.svc.VerifyEmail(r.Context(), query.Token); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("Email verified")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerification emails the authenticated user a new verification link.
+func (c *AccountController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("ResendVerification handler invoked")
+
+	user, ok := currentUser(w, r, c.users)
+	if !ok {
+		return
+	}
+	if err := c.svc.SendVerification(r.Context(), user); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
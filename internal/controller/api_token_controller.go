@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"encoding/json"
+	"go-crud-oapi/internal/middleware"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type APITokenController struct {
+	svc service.APITokenServiceInterface
+}
+
+func NewAPITokenController(svc service.APITokenServiceInterface) *APITokenController {
+	return &APITokenController{svc: svc}
+}
+
+// ListMyTokens lists the authenticated user's API tokens.
+func (c *APITokenController) ListMyTokens(w http.ResponseWriter, r *http.Request) {
+	logger.L(r.Context()).Info("ListMyTokens handler invoked")
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+	c.list(w, r, id)
+}
+
+// CreateMyToken creates an API token for the authenticated user.
+func (c *APITokenController) CreateMyToken(w http.ResponseWriter, r *http.Request) {
+	logger.L(r.Context()).Info("CreateMyToken handler invoked")
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+	c.create(w, r, id)
+}
+
+// RevokeMyToken revokes one of the authenticated user's API tokens.
+func (c *APITokenController) RevokeMyToken(w http.ResponseWriter, r *http.Request, tokenID int) {
+	logger.L(r.Context()).Info("RevokeMyToken handler invoked", zap.Int("token_id", tokenID))
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+	c.revoke(w, r, id, uint(tokenID))
+}
+
+// ListUserTokens lists the API tokens of any user or service account.
+func (c *APITokenController) ListUserTokens(w http.ResponseWriter, r *http.Request, id int) {
+	logger.L(r.Context()).Info("ListUserTokens handler invoked", zap.Int("user_id", id))
+	c.list(w, r, uint(id))
+}
+
+// CreateUserToken creates an API token for any user or service account.
+func (c *APITokenController) CreateUserToken(w http.ResponseWriter, r *http.Request, id int) {
+	logger.L(r.Context()).Info("CreateUserToken handler invoked", zap.Int("user_id", id))
+	c.create(w, r, uint(id))
+}
+
+// RevokeUserToken revokes an API token of any user or service account.
+func (c *APITokenController) RevokeUserToken(w http.ResponseWriter, r *http.Request, id int, tokenID int) {
+	logger.L(r.Context()).Info("RevokeUserToken handler invoked", zap.Int("user_id", id), zap.Int("token_id", tokenID))
+	c.revoke(w, r, uint(id), uint(tokenID))
+}
+
+// CreateServiceAccount creates a non-human account that authenticates with API tokens.
+func (c *APITokenController) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("CreateServiceAccount handler invoked")
+
+	var req model.CreateServiceAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	user, err := c.svc.CreateServiceAccount(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("Service account created", zap.Uint("user_id", user.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toAdminUserResponse(user))
+}
+
+// ListServiceAccounts lists every service account.
+func (c *APITokenController) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("ListServiceAccounts handler invoked")
+
+	users, err := c.svc.ListServiceAccounts(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := model.UserListResponse{Items: make([]any, len(users))}
+	for i := range users {
+		resp.Items[i] = toAdminUserResponse(&users[i])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (c *APITokenController) list(w http.ResponseWriter, r *http.Request, userID uint) {
+	tokens, err := c.svc.List(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := make([]model.APITokenResponse, len(tokens))
+	for i := range tokens {
+		resp[i] = toAPITokenResponse(&tokens[i])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"items": resp})
+}
+
+func (c *APITokenController) create(w http.ResponseWriter, r *http.Request, userID uint) {
+	log := logger.L(r.Context())
+
+	var req model.CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	mfa, _ := r.Context().Value(middleware.MFAKey).(bool)
+	token, secret, err := c.svc.Create(r.Context(), userID, req, mfa)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("API token created", zap.Uint("user_id", userID), zap.Uint("token_id", token.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(model.APITokenCreatedResponse{APITokenResponse: toAPITokenResponse(token), Token: secret})
+}
+
+func (c *APITokenController) revoke(w http.ResponseWriter, r *http.Request, userID, tokenID uint) {
+	if err := c.svc.Revoke(r.Context(), userID, tokenID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	logger.L(r.Context()).Info("API token revoked", zap.Uint("user_id", userID), zap.Uint("token_id", tokenID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toAPITokenResponse(t *model.APIToken) model.APITokenResponse {
+	return model.APITokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     strings.Split(t.Scopes, ","),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		RevokedAt:  t.RevokedAt,
+	}
+}
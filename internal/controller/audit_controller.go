@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-oapi/internal/handler"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+type AuditController struct {
+	svc service.AuditServiceInterface
+}
+
+func NewAuditController(svc service.AuditServiceInterface) *AuditController {
+	return &AuditController{svc: svc}
+}
+
+func (c *AuditController) ListAuditLogs(w http.ResponseWriter, r *http.Request, query handler.ListAuditLogsParams) {
+	log := logger.L(r.Context())
+	log.Info("ListAuditLogs handler invoked")
+
+	params, err := parseAuditParams(query)
+	if err != nil {
+		log.Warn("Invalid audit list parameters", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(err.Error()))
+		return
+	}
+
+	page, err := c.svc.List(r.Context(), params, deref(query.Cursor))
+	if errors.Is(err, service.ErrInvalidCursor) {
+		log.Warn("Invalid pagination cursor", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(err.Error()))
+		return
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("Successfully retrieved audit logs", zap.Int("count", len(page.Items)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAuditListResponse(page))
+}
+
+// parseAuditParams checks the audit query parameters, already decoded by the
+// generated handler, and converts them for the service.
+func parseAuditParams(query handler.ListAuditLogsParams) (model.AuditListParams, error) {
+	params := model.AuditListParams{
+		Action: string(deref(query.Action)),
+		From:   query.From,
+		To:     query.To,
+	}
+
+	if query.Limit != nil {
+		if *query.Limit < 1 || *query.Limit > service.MaxPageSize {
+			return params, fmt.Errorf("limit must be between 1 and %d", service.MaxPageSize)
+		}
+		params.Limit = *query.Limit
+	}
+
+	if query.ActorUserId != nil {
+		if *query.ActorUserId < 1 {
+			return params, fmt.Errorf("actor_user_id must be a positive integer")
+		}
+		actor := uint(*query.ActorUserId)
+		params.ActorUserID = &actor
+	}
+
+	if query.TargetUserId != nil {
+		if *query.TargetUserId < 1 {
+			return params, fmt.Errorf("target_user_id must be a positive integer")
+		}
+		target := uint(*query.TargetUserId)
+		params.TargetUserID = &target
+	}
+
+	return params, nil
+}
+
+func toAuditListResponse(page *model.AuditPage) model.AuditListResponse {
+	items := make([]model.AuditLogResponse, 0, len(page.Items))
+	for _, e := range page.Items {
+		items = append(items, model.AuditLogResponse{
+			ID:           e.ID,
+			Actor:        e.Actor,
+			ActorUserID:  e.ActorUserID,
+			ActorTokenID: e.ActorTokenID,
+			Action:       e.Action,
+			TargetUserID: e.TargetUserID,
+			Changes:      json.RawMessage(e.Changes),
+			RequestID:    e.RequestID,
+			CreatedAt:    e.CreatedAt,
+		})
+	}
+	return model.AuditListResponse{Items: items, NextCursor: page.NextCursor}
+}
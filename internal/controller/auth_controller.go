@@ -2,20 +2,24 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"go-crud-oapi/internal/middleware"
+	"go-crud-oapi/internal/model"
 	"go-crud-oapi/internal/repository"
-	"go-crud-oapi/pkg/auth"
-	"log"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/problem"
+	"io"
 	"net/http"
-
-	"golang.org/x/crypto/bcrypt"
+	"time"
 )
 
 type AuthController struct {
 	Repo repository.UserRepoInterface
+	svc  service.AuthServiceInterface
 }
 
-func NewAuthController(repo repository.UserRepoInterface) *AuthController {
-	return &AuthController{Repo: repo}
+func NewAuthController(repo repository.UserRepoInterface, svc service.AuthServiceInterface) *AuthController {
+	return &AuthController{Repo: repo, svc: svc}
 }
 
 func (a *AuthController) Login(w http.ResponseWriter, r *http.Request) {
@@ -24,34 +28,89 @@ func (a *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 		Password string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
 		return
 	}
 
-	user, err := a.Repo.FindByEmail(r.Context(), creds.Email)
-	if err != nil || user == nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	user, err := a.svc.Authenticate(r.Context(), creds.Email, creds.Password)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(creds.Password)); err != nil {
-		http.Error(w, "Invalid password", http.StatusUnauthorized)
+	if user.TOTPEnabled {
+		challenge, err := a.svc.BeginMFA(r.Context(), user)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(challenge)
 		return
 	}
 
-	if user.Role != "admin" {
-		http.Error(w, "Unauthorized - admin access only", http.StatusForbidden)
+	tokens, err := a.svc.IssueTokens(r.Context(), user)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	token, err := auth.GenerateToken(user.Email, user.Role)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
 
+}
+
+// VerifyMFA is the second login step: it exchanges the challenge token from
+// Login and a TOTP or recovery code for access and refresh tokens.
+func (a *AuthController) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req model.MFALoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MFAToken == "" {
+		problem.Write(w, r, problem.InvalidRequest("an mfa_token and a code or recovery_code are required"))
+		return
+	}
+
+	tokens, err := a.svc.CompleteMFA(r.Context(), req)
 	if err != nil {
-		log.Printf("JWT Signing failed: %v", err)
-		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (a *AuthController) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		problem.Write(w, r, problem.InvalidRequest("a refresh_token is required"))
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"token": token})
+	tokens, err := a.svc.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (a *AuthController) Logout(w http.ResponseWriter, r *http.Request) {
+	// The body is optional: without a refresh token only the access token is revoked
+	var req model.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	jti, _ := r.Context().Value(middleware.TokenIDKey).(string)
+	exp, _ := r.Context().Value(middleware.TokenExpiryKey).(time.Time)
+
+	if err := a.svc.Logout(r.Context(), req.RefreshToken, jti, exp); err != nil {
+		writeError(w, r, err)
+		return
+	}
 
+	w.WriteHeader(http.StatusNoContent)
 }
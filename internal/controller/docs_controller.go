@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// SpecPath is where DocsController serves the spec and where Swagger UI
+// loads it from.
+const SpecPath = "/openapi.json"
+
+// swaggerInitializer replaces the initializer shipped with Swagger UI, which
+// points at the petstore example.
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    persistAuthorization: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// DocsController serves api/openapi.yaml and a Swagger UI page for it.
+type DocsController struct {
+	spec []byte
+	ui   http.Handler
+}
+
+// NewDocsController renders spec as JSON once.
+func NewDocsController(swagger *openapi3.T) (*DocsController, error) {
+	spec, err := json.Marshal(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("encode spec: %w", err)
+	}
+	return &DocsController{
+		spec: spec,
+		ui:   http.StripPrefix("/docs", http.FileServerFS(swaggerFiles.FS)),
+	}, nil
+}
+
+// OpenAPISpec serves the OpenAPI document the API is generated from.
+func (c *DocsController) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(c.spec)
+}
+
+// SwaggerUI serves the Swagger UI assets below /docs/.
+func (c *DocsController) SwaggerUI(w http.ResponseWriter, r *http.Request) {
+	c.ui.ServeHTTP(w, r)
+}
+
+// SwaggerInitializer points Swagger UI at OpenAPISpec.
+func (c *DocsController) SwaggerInitializer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	fmt.Fprintf(w, swaggerInitializer, SpecPath)
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/problem"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// userETag is a strong entity tag derived from the user's version column.
+func userETag(u *model.User) string {
+	return `"` + strconv.FormatUint(uint64(u.Version), 10) + `"`
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header value lists
+// etag or "*". If-Match uses strong comparison, so weak tags never match it.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch evaluates If-Match against the current user and writes 412 (or
+// 428 when the header is required but missing) if the write must not proceed.
+// It returns the version the write should be conditioned on, 0 meaning none.
+func (c *UserController) checkIfMatch(w http.ResponseWriter, r *http.Request, current *model.User) (uint, bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		if c.requireIfMatch {
+			problem.Write(w, r, problem.Status(http.StatusPreconditionRequired, "send the user's current ETag in If-Match"))
+			return 0, false
+		}
+		return 0, true
+	}
+
+	if !etagMatches(ifMatch, userETag(current), false) {
+		problem.Write(w, r, problem.Status(http.StatusPreconditionFailed, "If-Match does not match the user's current ETag"))
+		return 0, false
+	}
+	return current.Version, true
+}
@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"encoding/json"
+	"go-crud-oapi/pkg/auth"
+	"net/http"
+)
+
+type JWKSController struct {
+	keys *auth.KeyManager
+}
+
+func NewJWKSController(keys *auth.KeyManager) *JWKSController {
+	return &JWKSController{keys: keys}
+}
+
+// JWKS publishes the public keys that verify access tokens so other services
+// can check them without sharing a secret.
+func (c *JWKSController) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(c.keys.JWKS())
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-crud-oapi/internal/handler"
+	"go-crud-oapi/internal/middleware"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// GetMe returns the full record of the authenticated user.
+func (c *UserController) GetMe(w http.ResponseWriter, r *http.Request, _ handler.GetMeParams) {
+	log := logger.L(r.Context())
+	log.Info("GetMe handler invoked")
+
+	user, ok := currentUser(w, r, c.svc)
+	if !ok {
+		return
+	}
+
+	etag := userETag(user)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Authorization")
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAdminUserResponse(user))
+}
+
+// PatchMe applies a merge-patch or json-patch document to the authenticated
+// user's name and phone. Any other field, including role, is rejected.
+func (c *UserController) PatchMe(w http.ResponseWriter, r *http.Request, _ handler.PatchMeParams) {
+	log := logger.L(r.Context())
+	log.Info("PatchMe handler invoked")
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Warn("Failed to read request body", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest("the request body could not be read"))
+		return
+	}
+
+	current, ok := currentUser(w, r, c.svc)
+	if !ok {
+		return
+	}
+	if _, ok := c.checkIfMatch(w, r, current); !ok {
+		log.Warn("If-Match precondition failed", zap.Uint("user_id", current.ID), zap.String("if_match", r.Header.Get("If-Match")))
+		return
+	}
+
+	doc, err := json.Marshal(userToMeRequest(current))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	patched, ok := patchDocument(w, r, doc, body)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateMeRequest
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		log.Warn("Patch touches fields users cannot change", zap.Error(err))
+		problem.Write(w, r, problem.Forbidden("only name and phone can be changed here"))
+		return
+	}
+
+	c.saveUser(w, r, current.ID, meRequestToUpdateRequest(req, current), current.Version)
+}
+
+// ChangeMyPassword changes the authenticated user's password after checking
+// the current one.
+func (c *UserController) ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("ChangeMyPassword handler invoked")
+
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+	c.changePassword(w, r, id)
+}
+
+// currentUser loads the user named by the token subject, writing the problem
+// if it cannot, such as 404 when the account no longer exists.
+func currentUser(w http.ResponseWriter, r *http.Request, users service.UserServiceInterFace) (*model.User, bool) {
+	log := logger.L(r.Context())
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+
+	user, err := users.Get(r.Context(), id)
+	if err != nil {
+		log.Warn("Authenticated user not found", zap.Uint("user_id", id))
+		writeError(w, r, err)
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"encoding/json"
+	"go-crud-oapi/internal/middleware"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+type MFAController struct {
+	svc service.MFAServiceInterface
+}
+
+func NewMFAController(svc service.MFAServiceInterface) *MFAController {
+	return &MFAController{svc: svc}
+}
+
+// EnrollTOTP starts TOTP enrollment for the authenticated user, who must give
+// their current password, and returns the secret, otpauth URI and QR code to
+// scan.
+func (c *MFAController) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("EnrollTOTP handler invoked")
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+
+	var req model.TOTPEnrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	enrollment, err := c.svc.Enroll(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("TOTP enrollment started", zap.Uint("user_id", id))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+// ConfirmTOTP enables TOTP once the user proves their authenticator works,
+// returning recovery codes that are never shown again.
+func (c *MFAController) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("ConfirmTOTP handler invoked")
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+
+	var req model.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	codes, err := c.svc.Confirm(r.Context(), id, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("TOTP enabled", zap.Uint("user_id", id))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(codes)
+}
+
+// DisableTOTP turns TOTP off after checking a current code.
+func (c *MFAController) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	log := logger.L(r.Context())
+	log.Info("DisableTOTP handler invoked")
+	id, _ := r.Context().Value(middleware.UserIDKey).(uint)
+
+	var req model.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	if err := c.svc.Disable(r.Context(), id, req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("TOTP disabled", zap.Uint("user_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"errors"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"mime"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json" // RFC 7386
+	jsonPatchContentType  = "application/json-patch+json"  // RFC 6902
+)
+
+var errUnsupportedPatchType = errors.New("unsupported patch content type")
+
+// applyPatch applies the PATCH body to doc using the format named by contentType.
+func applyPatch(contentType string, doc, body []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errUnsupportedPatchType
+	}
+
+	switch mediaType {
+	case mergePatchContentType:
+		return jsonpatch.MergePatch(doc, body)
+	case jsonPatchContentType:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return nil, err
+		}
+		return patch.Apply(doc)
+	default:
+		return nil, errUnsupportedPatchType
+	}
+}
+
+// patchDocument applies the request's PATCH body to doc, writing 415 or 400 if
+// it cannot.
+func patchDocument(w http.ResponseWriter, r *http.Request, doc, body []byte) ([]byte, bool) {
+	log := logger.L(r.Context())
+	contentType := r.Header.Get("Content-Type")
+
+	patched, err := applyPatch(contentType, doc, body)
+	if errors.Is(err, errUnsupportedPatchType) {
+		log.Warn("Unsupported patch content type", zap.String("content_type", contentType))
+		problem.Write(w, r, problem.Status(http.StatusUnsupportedMediaType,
+			"use "+mergePatchContentType+" or "+jsonPatchContentType))
+		return nil, false
+	}
+	if err != nil {
+		log.Warn("Patch could not be applied", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest("the patch could not be applied: "+err.Error()))
+		return nil, false
+	}
+	return patched, true
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"errors"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"go-crud-oapi/pkg/validation"
+	"net/http"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// invalidJSONDetail explains a 400 for a body that does not decode.
+const invalidJSONDetail = "the request body is not valid JSON for this operation"
+
+// writeError answers with the problem for err's type in the service error
+// taxonomy. Anything outside it is logged and answered with a bare 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	p := toProblem(err)
+	log := logger.L(r.Context())
+	if p.Status >= http.StatusInternalServerError {
+		log.Error("Request failed", zap.Error(err))
+	} else {
+		log.Warn("Request rejected", zap.Int("status", p.Status), zap.Error(err))
+	}
+	problem.Write(w, r, p)
+}
+
+func toProblem(err error) *problem.Problem {
+	var (
+		verr         *validation.Error
+		notFound     *service.NotFoundError
+		conflict     *service.ConflictError
+		invalid      *service.InvalidRequestError
+		unauthorized *service.UnauthorizedError
+		forbidden    *service.ForbiddenError
+	)
+	switch {
+	case errors.As(err, &verr):
+		return problem.Validation(verr.Fields)
+	case errors.As(err, &notFound):
+		return problem.New(problem.TypeNotFound, http.StatusNotFound, notFound.Error()).With("resource", notFound.Resource)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return problem.New(problem.TypeNotFound, http.StatusNotFound, "")
+	case errors.As(err, &conflict):
+		p := problem.New(problem.TypeConflict, http.StatusConflict, conflict.Reason).With("resource", conflict.Resource)
+		if conflict.Field != "" {
+			p.With("field", conflict.Field)
+		}
+		return p
+	case errors.As(err, &invalid):
+		return problem.InvalidRequest(invalid.Reason)
+	case errors.As(err, &unauthorized):
+		return problem.Unauthorized(unauthorized.Reason)
+	case errors.As(err, &forbidden):
+		return problem.Forbidden(forbidden.Reason)
+	case errors.Is(err, service.ErrVersionConflict):
+		return problem.Status(http.StatusPreconditionFailed, "the user was modified by another request")
+	}
+	return problem.Status(http.StatusInternalServerError, "")
+}
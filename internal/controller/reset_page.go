@@ -0,0 +1,73 @@
+package controller
+
+import "net/http"
+
+// resetPasswordPage is the page the password reset email links to. It reads
+// the token from its own URL and posts it with the new password to
+// POST /password/reset, showing the problem detail if that fails.
+const resetPasswordPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Choose a new password</title>
+<style>
+body { font-family: sans-serif; max-width: 24rem; margin: 4rem auto; padding: 0 1rem; }
+label, input, button { display: block; width: 100%; box-sizing: border-box; margin-top: .5rem; }
+#message { margin-top: 1rem; }
+</style>
+</head>
+<body>
+<h1>Choose a new password</h1>
+<form id="reset">
+<label for="password">New password</label>
+<input id="password" type="password" autocomplete="new-password" required>
+<label for="confirm">Repeat new password</label>
+<input id="confirm" type="password" autocomplete="new-password" required>
+<button type="submit">Set password</button>
+</form>
+<p id="message" role="status"></p>
+<script>
+const form = document.getElementById("reset");
+const message = document.getElementById("message");
+const token = new URLSearchParams(location.search).get("token");
+if (!token) {
+  form.hidden = true;
+  message.textContent = "This link is incomplete. Open the link from the email again.";
+}
+form.addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const password = document.getElementById("password").value;
+  if (password !== document.getElementById("confirm").value) {
+    message.textContent = "The passwords do not match.";
+    return;
+  }
+  const res = await fetch(location.pathname, {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({token: token, new_password: password}),
+  });
+  if (res.ok) {
+    form.hidden = true;
+    message.textContent = "Your password has been changed. You can now log in with it.";
+    return;
+  }
+  const problem = await res.json().catch(() => ({}));
+  const errors = (problem.errors || []).map((e) => e.message).join(" ");
+  message.textContent = [problem.detail || problem.title || "Something went wrong.", errors].join(" ");
+});
+</script>
+</body>
+</html>
+`
+
+// ResetPasswordPage serves the form for a password reset link. It is plain
+// HTML outside api/openapi.yaml; the reset itself is ResetPassword. The token
+// is in the URL, so the page is not cached and sends no referrer.
+func (c *AccountController) ResetPasswordPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'; form-action 'none'")
+	w.Write([]byte(resetPasswordPage))
+}
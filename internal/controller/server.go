@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"go-crud-oapi/internal/handler"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// Server implements handler.ServerInterface, the operations generated from
+// api/openapi.yaml, by embedding the controller for each area of the API.
+type Server struct {
+	*UserController
+	*AuthController
+	*AccountController
+	*MFAController
+	*APITokenController
+	*AuditController
+	*JWKSController
+}
+
+var _ handler.ServerInterface = (*Server)(nil)
+
+// ParamError answers 400 when the generated handler cannot decode a path,
+// query or header parameter.
+func ParamError(w http.ResponseWriter, r *http.Request, err error) {
+	logger.L(r.Context()).Warn("Invalid request parameter", zap.Error(err))
+	problem.Write(w, r, problem.InvalidRequest(err.Error()))
+}
@@ -1,162 +1,344 @@
 package controller
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"go-crud-oapi/internal/handler"
+	"go-crud-oapi/internal/middleware"
 	"go-crud-oapi/internal/model"
 	"go-crud-oapi/internal/repository"
 	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/auth"
 	"go-crud-oapi/pkg/logger"
-	"go-crud-oapi/pkg/utils"
+	"go-crud-oapi/pkg/problem"
+	"io"
 	"net/http"
-	"strconv"
+	"strings"
 
-	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
 
 type UserController struct {
-	Repo repository.UserRepoInterface
-	svc  service.UserServiceInterFace
+	Repo     repository.UserRepoInterface
+	svc      service.UserServiceInterFace
+	accounts service.AccountServiceInterface
+
+	requireIfMatch bool // reject PUT/PATCH/DELETE without If-Match with 428
 }
 
-func NewUserController(svc service.UserServiceInterFace) *UserController {
-	return &UserController{svc: svc}
+func NewUserController(svc service.UserServiceInterFace, accounts service.AccountServiceInterface, requireIfMatch bool) *UserController {
+	return &UserController{svc: svc, accounts: accounts, requireIfMatch: requireIfMatch}
 }
 
-func (c *UserController) ListUsers(w http.ResponseWriter, r *http.Request) {
+func (c *UserController) ListUsers(w http.ResponseWriter, r *http.Request, query handler.ListUsersParams) {
 	log := logger.L(r.Context())
 	log.Info("ListUsers handler invoked")
 
-	users, err := c.svc.ListAllUsers(r.Context())
+	params, err := parseListParams(r, query)
+	if errors.Is(err, errPrivateFilter) {
+		log.Warn("Filter on private field without permission", zap.Error(err))
+		problem.Write(w, r, problem.Forbidden(err.Error()))
+		return
+	}
+	if err != nil {
+		log.Warn("Invalid list parameters", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(err.Error()))
+		return
+	}
+
+	page, err := c.svc.ListUsers(r.Context(), params, deref(query.Cursor))
+	if errors.Is(err, service.ErrInvalidCursor) {
+		log.Warn("Invalid pagination cursor", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(err.Error()))
+		return
+	}
 	if err != nil {
-		log.Error("Failed to list users", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	log.Info("Successfully retrieved users", zap.Int("count", len(users)))
-	json.NewEncoder(w).Encode(users)
+	log.Info("Successfully retrieved users", zap.Int("count", len(page.Items)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toUserListResponse(page, userViewer(r)))
 }
 
-func (c *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
+func (c *UserController) CreateUser(w http.ResponseWriter, r *http.Request, _ handler.CreateUserParams) {
 	log := logger.L(r.Context())
 	log.Info("CreateUser handler invoked")
 
-	var user model.User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	var req model.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("Invalid request payload", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusBadRequest)
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
 		return
 	}
+	user := createRequestToUser(req)
 
-	// Check if email already exists
-	existing, err := c.svc.GetUserByEmail(r.Context(), user.Email)
-	if err != nil {
-		log.Error("Failed to check email existence", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusInternalServerError)
-		//http.Error(w, "Server error", http.StatusInternalServerError)
+	if err := c.svc.Create(r.Context(), &user); err != nil {
+		writeError(w, r, err)
 		return
 	}
-	if existing != nil {
-		log.Warn("Email already in use", zap.String("email", user.Email))
-		utils.WriteJSONError(w, http.StatusConflict)
+
+	log.Info("User created successfully", zap.Uint("user_id", user.ID))
+	if err := c.accounts.SendVerification(r.Context(), &user); err != nil {
+		log.Error("Failed to send verification email", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toAdminUserResponse(&user))
+}
+
+func (c *UserController) GetUser(w http.ResponseWriter, r *http.Request, id int, query handler.GetUserParams) {
+	log := logger.L(r.Context())
+	log.Info("GetUser handler invoked", zap.Int("user_id", id))
+
+	get := c.svc.Get
+	if includeDeleted(r, query.IncludeDeleted) {
+		get = c.svc.GetIncludingDeleted
+	}
+	user, err := get(r.Context(), uint(id))
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := c.svc.Create(r.Context(), &user); err != nil {
-		log.Error("Failed to create user", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusInternalServerError)
+	etag := userETag(user)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Authorization")
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag, true) {
+		log.Info("User not modified", zap.Uint("user_id", user.ID))
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
-	log.Info("User created successfully", zap.Uint("user_id", user.ID))
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(user)
+	log.Info("User retrieved", zap.Uint("user_id", user.ID))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(userViewer(r)(user))
 }
 
-func (c *UserController) GetUser(w http.ResponseWriter, r *http.Request) {
+// UpdateUser replaces every field of the user with the request body. An
+// omitted password leaves the current one unchanged.
+func (c *UserController) UpdateUser(w http.ResponseWriter, r *http.Request, id int, _ handler.UpdateUserParams) {
 	log := logger.L(r.Context())
-	idParam := chi.URLParam(r, "id")
-	log.Info("GetUser handler invoked", zap.String("user_id_param", idParam))
+	log.Info("UpdateUser handler invoked", zap.Int("user_id", id))
 
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		log.Warn("Invalid user ID", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusBadRequest)
-		//http.Error(w, "Invalid ID", http.StatusBadRequest)
+	var req model.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
 		return
 	}
 
-	user, err := c.svc.Get(r.Context(), uint(id))
+	current, err := c.svc.Get(r.Context(), uint(id))
 	if err != nil {
-		log.Error("User not found", zap.Int("user_id", id), zap.Error(err))
-		utils.WriteJSONError(w, http.StatusNotFound)
+		writeError(w, r, err)
+		return
+	}
+	version, ok := c.checkIfMatch(w, r, current)
+	if !ok {
+		log.Warn("If-Match precondition failed", zap.Int("user_id", id), zap.String("if_match", r.Header.Get("If-Match")))
 		return
 	}
 
-	log.Info("User retrieved", zap.Uint("user_id", user.ID))
-	json.NewEncoder(w).Encode(user)
+	c.saveUser(w, r, uint(id), req, version)
 }
 
-func (c *UserController) UpdateUser(w http.ResponseWriter, r *http.Request) {
+// PatchUser applies an application/merge-patch+json or
+// application/json-patch+json document to the user's current representation.
+func (c *UserController) PatchUser(w http.ResponseWriter, r *http.Request, id int, _ handler.PatchUserParams) {
 	log := logger.L(r.Context())
-	idParam := chi.URLParam(r, "id")
-	log.Info("UpdateUser handler invoked", zap.String("user_id_param", idParam))
+	log.Info("PatchUser handler invoked", zap.Int("user_id", id))
 
-	id, err := strconv.Atoi(idParam)
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		log.Warn("Invalid user ID", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusBadRequest)
+		log.Warn("Failed to read request body", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest("the request body could not be read"))
 		return
 	}
 
-	var user model.User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		log.Warn("Invalid request payload", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusBadRequest)
+	current, err := c.svc.Get(r.Context(), uint(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if _, ok := c.checkIfMatch(w, r, current); !ok {
+		log.Warn("If-Match precondition failed", zap.Int("user_id", id), zap.String("if_match", r.Header.Get("If-Match")))
 		return
 	}
 
-	existing, err := c.svc.GetUserByEmail(r.Context(), user.Email)
+	doc, err := json.Marshal(userToUpdateRequest(current))
 	if err != nil {
-		log.Error("Failed to check email existence", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	if existing != nil {
-		log.Warn("Email already in use", zap.String("email", user.Email))
-		utils.WriteJSONError(w, http.StatusConflict)
+
+	patched, ok := patchDocument(w, r, doc, body)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateUserRequest
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		log.Warn("Patched user is not a valid user document", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest("the patched user is not a valid user: "+err.Error()))
 		return
 	}
 
-	if err := c.svc.Update(r.Context(), uint(id), &user); err != nil {
-		log.Error("Failed to update user", zap.Int("user_id", id), zap.Error(err))
-		utils.WriteJSONError(w, http.StatusInternalServerError)
+	// The patch was computed against current, so never write over a newer version.
+	c.saveUser(w, r, uint(id), req, current.Version)
+}
+
+// saveUser writes req as the complete new state of user id, shared by PUT and
+// PATCH. A non-zero version makes the write conditional on it still being current.
+func (c *UserController) saveUser(w http.ResponseWriter, r *http.Request, id uint, req model.UpdateUserRequest, version uint) {
+	log := logger.L(r.Context())
+	user := updateRequestToUser(req)
+	user.Version = version
+
+	if err := c.svc.Update(r.Context(), id, &user); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	log.Info("User updated successfully", zap.Uint("user_id", user.ID))
-	json.NewEncoder(w).Encode(user)
+	w.Header().Set("ETag", userETag(&user))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAdminUserResponse(&user))
 }
 
-func (c *UserController) DeleteUser(w http.ResponseWriter, r *http.Request) {
+func (c *UserController) DeleteUser(w http.ResponseWriter, r *http.Request, id int, _ handler.DeleteUserParams) {
 	log := logger.L(r.Context())
-	idParam := chi.URLParam(r, "id")
-	log.Info("DeleteUser handler invoked", zap.String("user_id_param", idParam))
+	log.Info("DeleteUser handler invoked", zap.Int("user_id", id))
 
-	id, err := strconv.Atoi(idParam)
+	current, err := c.svc.Get(r.Context(), uint(id))
 	if err != nil {
-		log.Warn("Invalid user ID", zap.Error(err))
-		utils.WriteJSONError(w, http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+	version, ok := c.checkIfMatch(w, r, current)
+	if !ok {
+		log.Warn("If-Match precondition failed", zap.Int("user_id", id), zap.String("if_match", r.Header.Get("If-Match")))
 		return
 	}
 
-	if err := c.svc.Delete(r.Context(), uint(id)); err != nil {
-		log.Error("Failed to delete user", zap.Int("user_id", id), zap.Error(err))
-		utils.WriteJSONError(w, http.StatusNoContent)
+	if err := c.svc.Delete(r.Context(), uint(id), version); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	log.Info("User deleted successfully", zap.Int("user_id", id))
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (c *UserController) ChangePassword(w http.ResponseWriter, r *http.Request, id int) {
+	log := logger.L(r.Context())
+	log.Info("ChangePassword handler invoked", zap.Int("user_id", id))
+
+	c.changePassword(w, r, uint(id))
+}
+
+// changePassword handles a ChangePasswordRequest for user id, shared by
+// POST /users/{id}/password and POST /me/password.
+func (c *UserController) changePassword(w http.ResponseWriter, r *http.Request, id uint) {
+	log := logger.L(r.Context())
+
+	var req model.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("Invalid request payload", zap.Error(err))
+		problem.Write(w, r, problem.InvalidRequest(invalidJSONDetail))
+		return
+	}
+
+	if err := c.svc.ChangePassword(r.Context(), id, req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("Password changed successfully", zap.Uint("user_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *UserController) RestoreUser(w http.ResponseWriter, r *http.Request, id int) {
+	log := logger.L(r.Context())
+	log.Info("RestoreUser handler invoked", zap.Int("user_id", id))
+
+	if err := c.svc.Restore(r.Context(), uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := c.svc.Get(r.Context(), uint(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("User restored successfully", zap.Int("user_id", id))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAdminUserResponse(user))
+}
+
+// UnlockUser clears a lockout caused by failed logins.
+func (c *UserController) UnlockUser(w http.ResponseWriter, r *http.Request, id int) {
+	log := logger.L(r.Context())
+	log.Info("UnlockUser handler invoked", zap.Int("user_id", id))
+
+	if err := c.svc.Unlock(r.Context(), uint(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Info("User unlocked", zap.Int("user_id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// includeDeleted reports whether a caller allowed to restore users asked to see
+// soft-deleted ones.
+func includeDeleted(r *http.Request, asked *bool) bool {
+	return middleware.Allowed(r.Context(), auth.PermUsersRestore) && asked != nil && *asked
+}
+
+// parseListParams checks the list query parameters, already decoded by the
+// generated handler, and converts them for the service.
+func parseListParams(r *http.Request, query handler.ListUsersParams) (model.UserListParams, error) {
+	params := model.UserListParams{
+		Role:   string(deref(query.Role)),
+		Email:  deref(query.Email),
+		Name:   deref(query.Name),
+		MinAge: query.MinAge,
+		MaxAge: query.MaxAge,
+
+		IncludeDeleted: includeDeleted(r, query.IncludeDeleted),
+	}
+
+	if query.Limit != nil {
+		if *query.Limit < 1 || *query.Limit > service.MaxPageSize {
+			return params, fmt.Errorf("limit must be between 1 and %d", service.MaxPageSize)
+		}
+		params.Limit = *query.Limit
+	}
+
+	sort := string(deref(query.Sort))
+	if strings.HasPrefix(sort, "-") {
+		params.SortDesc = true
+		sort = sort[1:]
+	}
+	switch sort {
+	case "", "id", "name", "email", "age":
+		params.SortBy = sort
+	default:
+		return params, fmt.Errorf("unsupported sort field %q", sort)
+	}
+
+	// Filtering or sorting on a private field would reveal it one query at a time.
+	if !canReadPrivate(r) && (params.Role != "" || params.Email != "" || params.MinAge != nil || params.MaxAge != nil || params.SortBy == "email" || params.SortBy == "age") {
+		return params, errPrivateFilter
+	}
+
+	return params, nil
+}
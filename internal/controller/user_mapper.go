@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"go-crud-oapi/internal/model"
+	"time"
+)
+
+// Requests and responses are mapped field by field so that nothing added to
+// model.User (password hashes, internal columns) reaches the wire by accident.
+
+func createRequestToUser(req model.CreateUserRequest) model.User {
+	return model.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Age:      req.Age,
+		Role:     req.Role,
+		Password: req.Password,
+	}
+}
+
+func updateRequestToUser(req model.UpdateUserRequest) model.User {
+	return model.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Age:      req.Age,
+		Role:     req.Role,
+		Password: req.Password,
+	}
+}
+
+// userToUpdateRequest is the document a PATCH is applied to. The password is
+// left empty so a patch that does not mention it keeps the current one.
+func userToUpdateRequest(u *model.User) model.UpdateUserRequest {
+	return model.UpdateUserRequest{
+		Name:  u.Name,
+		Email: u.Email,
+		Phone: u.Phone,
+		Age:   u.Age,
+		Role:  u.Role,
+	}
+}
+
+// meRequestToUpdateRequest overlays the self-editable fields onto the user's
+// current state, so nothing else can be changed through /me.
+func meRequestToUpdateRequest(req model.UpdateMeRequest, u *model.User) model.UpdateUserRequest {
+	update := userToUpdateRequest(u)
+	update.Name = req.Name
+	update.Phone = req.Phone
+	return update
+}
+
+func userToMeRequest(u *model.User) model.UpdateMeRequest {
+	return model.UpdateMeRequest{Name: u.Name, Phone: u.Phone}
+}
+
+func toUserSummaryResponse(u *model.User) model.UserSummaryResponse {
+	return model.UserSummaryResponse{ID: u.ID, Name: u.Name}
+}
+
+func toUserResponse(u *model.User) model.UserResponse {
+	return model.UserResponse{
+		ID:        u.ID,
+		Name:      u.Name,
+		Role:      u.Role,
+		DeletedAt: deletedAt(u),
+	}
+}
+
+func toAdminUserResponse(u *model.User) model.AdminUserResponse {
+	return model.AdminUserResponse{
+		ID:    u.ID,
+		Name:  u.Name,
+		Email: u.Email,
+		Phone: u.Phone,
+		Age:   u.Age,
+		Role:  u.Role,
+
+		EmailVerified:  u.EmailVerifiedAt != nil,
+		ServiceAccount: u.ServiceAccount,
+
+		Version:     u.Version,
+		DeletedAt:   deletedAt(u),
+		LockedUntil: lockedUntil(u),
+	}
+}
+
+// lockedUntil is only reported while the lock is still in force.
+func lockedUntil(u *model.User) *time.Time {
+	if u.LockedUntil == nil || !u.LockedUntil.After(time.Now()) {
+		return nil
+	}
+	return u.LockedUntil
+}
+
+func deletedAt(u *model.User) *time.Time {
+	if !u.DeletedAt.Valid {
+		return nil
+	}
+	t := u.DeletedAt.Time
+	return &t
+}
+
+func toUserListResponse(page *model.UserPage, view func(*model.User) any) model.UserListResponse {
+	items := make([]any, 0, len(page.Items))
+	for i := range page.Items {
+		items = append(items, view(&page.Items[i]))
+	}
+	return model.UserListResponse{Items: items, NextCursor: page.NextCursor}
+}
+
+// deref returns the value of an optional generated parameter, or its zero value.
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"errors"
+	"go-crud-oapi/internal/middleware"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/auth"
+	"net/http"
+)
+
+var errPrivateFilter = errors.New("filtering on role, or filtering or sorting on email or age, requires users:read_private")
+
+// canReadPrivate reports whether the caller may see every user's email, phone and age.
+func canReadPrivate(r *http.Request) bool {
+	return middleware.Allowed(r.Context(), auth.PermUsersReadPrivate)
+}
+
+// userViewer returns the mapping that filters a user down to the fields the
+// caller may see: everything for their own record or with users:read_private,
+// the name only for viewers, and the public view for everyone else.
+func userViewer(r *http.Request) func(*model.User) any {
+	role, _ := r.Context().Value(middleware.UserRoleKey).(string)
+	self, _ := r.Context().Value(middleware.UserIDKey).(uint)
+	private := middleware.Allowed(r.Context(), auth.PermUsersReadPrivate)
+
+	return func(u *model.User) any {
+		switch {
+		case private || u.ID == self:
+			return toAdminUserResponse(u)
+		case role == "viewer":
+			return toUserSummaryResponse(u)
+		default:
+			return toUserResponse(u)
+		}
+	}
+}
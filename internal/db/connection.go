@@ -1,64 +1,82 @@
 package db
 
 import (
-	"database/sql"
-	"fmt"
+	"context"
+	"errors"
 	"log"
 
 	"go-crud-oapi/config"
-	"go-crud-oapi/internal/model"
 
-	_ "github.com/lib/pq"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-// Init initializes the database connection and runs migrations.
+// Init connects to the database and brings its schema up to date. It refuses
+// to continue if the schema is newer than the embedded migrations.
 func Init(cfg *config.Config) *gorm.DB {
+	gormDB, driver := Connect(cfg)
 
-	//dbDriver := cfg.DBDriver
-	host := cfg.DBHost
-	port := cfg.DBPort
-	user := cfg.DBUser
-	password := cfg.DBPassword
-	dbName := cfg.DBName
-
-	// Step 1: Connect to postgres system DB to check/create target DB
-	systemDSN := fmt.Sprintf("host=%s user=%s password=%s dbname=postgres port=%s sslmode=disable", host, user, password, port)
-	systemDB, err := sql.Open("postgres", systemDSN)
+	sqlDB, err := gormDB.DB()
 	if err != nil {
-		log.Fatal("❌ System DB connection failed:", err)
+		log.Fatal("❌ Failed to get sql.DB from GORM:", err)
 	}
-	defer systemDB.Close()
-
-	// Step 2: Check if DB exists
-	var exists bool
-	err = systemDB.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", dbName).Scan(&exists)
+	migrator, err := NewMigrator(sqlDB, driver)
 	if err != nil {
-		log.Fatal("❌ Failed to check DB existence:", err)
+		log.Fatal("❌ Failed to load migrations:", err)
+	}
+
+	ctx := context.Background()
+	if err := migrator.CheckCompatible(ctx); err != nil {
+		if errors.Is(err, ErrSchemaTooNew) {
+			log.Fatalf("❌ Refusing to start: %v", err)
+		}
+		log.Fatal("❌ Failed to read schema version:", err)
 	}
 
-	// Step 3: Create DB if not exists
-	if !exists {
-		_, err = systemDB.Exec("CREATE DATABASE " + dbName)
+	if cfg.DBAutoMigrate {
+		applied, err := migrator.Up(ctx)
 		if err != nil {
-			log.Fatalf("❌ Failed to create DB %s: %v", dbName, err)
+			log.Fatal("❌ Migration failed:", err)
 		}
-		log.Printf("✅ Database '%s' created", dbName)
+		log.Printf("✅ Database connected, %d migration(s) applied", applied)
+		return gormDB
+	}
+
+	current, err := migrator.CurrentVersion(ctx)
+	if err != nil {
+		log.Fatal("❌ Failed to read schema version:", err)
+	}
+	if current < migrator.LatestVersion() {
+		log.Printf("⚠️  Database schema is at version %d, latest is %d; run `migrate up`", current, migrator.LatestVersion())
 	}
+	log.Println("✅ Database connected")
+	return gormDB
+}
 
-	// Step 4: Connect to target DB using GORM's DSN
-	appDSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", host, user, password, dbName, port)
-	gormDB, err := gorm.Open(postgres.Open(appDSN), &gorm.Config{})
+// Connect opens the target database with the driver named by cfg.DBDriver,
+// creating the database first if the backend needs it.
+func Connect(cfg *config.Config) (*gorm.DB, Driver) {
+	driver, err := NewDriver(cfg.DBDriver)
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	if err := driver.EnsureDatabase(cfg); err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	// TranslateError turns unique index violations into gorm.ErrDuplicatedKey
+	// whichever backend reported them.
+	gormDB, err := gorm.Open(driver.Dialector(cfg), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("❌ GORM init failed:", err)
 	}
 
-	// Step 5: AutoMigrate
-	if err := gormDB.AutoMigrate(&model.User{}); err != nil {
-		log.Fatal("❌ AutoMigrate failed:", err)
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		log.Fatal("❌ Failed to get sql.DB from GORM:", err)
 	}
+	driver.Configure(sqlDB)
 
-	log.Println("✅ Database connected and migrated")
-	return gormDB
+	log.Printf("✅ Connected to %s database '%s'", driver.Name(), cfg.DBName)
+	return gormDB, driver
 }
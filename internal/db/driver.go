@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go-crud-oapi/config"
+
+	"gorm.io/gorm"
+)
+
+// Driver hides the differences between the supported database backends.
+type Driver interface {
+	// Name is the DB_DRIVER value and the migrations sub-directory.
+	Name() string
+	// EnsureDatabase creates the target database if the backend needs it.
+	EnsureDatabase(cfg *config.Config) error
+	// Dialector opens the target database through GORM.
+	Dialector(cfg *config.Config) gorm.Dialector
+	// Configure tunes the connection pool once the database is open.
+	Configure(sqlDB *sql.DB)
+	// Placeholder returns the bind parameter for the n-th (1-based) argument.
+	Placeholder(n int) string
+	// SchemaMigrationsDDL creates the migration bookkeeping table.
+	SchemaMigrationsDDL() string
+	// Lock and Unlock serialize migrations across instances on conn.
+	Lock(ctx context.Context, conn *sql.Conn) error
+	Unlock(ctx context.Context, conn *sql.Conn) error
+}
+
+// NewDriver returns the Driver registered for name.
+func NewDriver(name string) (Driver, error) {
+	switch name {
+	case "postgres", "postgresql":
+		return postgresDriver{}, nil
+	case "mysql":
+		return mysqlDriver{}, nil
+	case "sqlite", "sqlite3":
+		return sqliteDriver{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q (want postgres, mysql or sqlite)", name)
+	}
+}
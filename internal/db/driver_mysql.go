@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"go-crud-oapi/config"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// migrationLockName is the GET_LOCK name held while migrating.
+const migrationLockName = "go_crud_oapi_migrate"
+
+type mysqlDriver struct{}
+
+func (mysqlDriver) Name() string { return "mysql" }
+
+func (mysqlDriver) EnsureDatabase(cfg *config.Config) error {
+	serverDSN := fmt.Sprintf("%s:%s@tcp(%s:%s)/", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort)
+	serverDB, err := sql.Open("mysql", serverDSN)
+	if err != nil {
+		return fmt.Errorf("server connection failed: %w", err)
+	}
+	defer serverDB.Close()
+
+	res, err := serverDB.Exec("CREATE DATABASE IF NOT EXISTS `" + cfg.DBName + "`")
+	if err != nil {
+		return fmt.Errorf("failed to create DB %s: %w", cfg.DBName, err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("✅ Database '%s' created", cfg.DBName)
+	}
+	return nil
+}
+
+// Dialector enables multiStatements so a migration file can hold several
+// statements. MySQL commits DDL implicitly, so a failed migration may be
+// partially applied.
+func (mysqlDriver) Dialector(cfg *config.Config) gorm.Dialector {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true&loc=UTC&multiStatements=true",
+		cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	return mysql.Open(dsn)
+}
+
+func (mysqlDriver) Configure(*sql.DB) {}
+
+func (mysqlDriver) Placeholder(int) string { return "?" }
+
+func (mysqlDriver) SchemaMigrationsDDL() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       VARCHAR(255) NOT NULL,
+		applied_at DATETIME(6) NOT NULL
+	)`
+}
+
+func (mysqlDriver) Lock(ctx context.Context, conn *sql.Conn) error {
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", migrationLockName).Scan(&got); err != nil {
+		return err
+	}
+	if got.Int64 != 1 {
+		return fmt.Errorf("GET_LOCK(%s) was not granted", migrationLockName)
+	}
+	return nil
+}
+
+func (mysqlDriver) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLockName)
+	return err
+}
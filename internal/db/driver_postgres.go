@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"go-crud-oapi/config"
+
+	_ "github.com/lib/pq"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// migrationLockKey is the pg_advisory_lock key held while migrating.
+const migrationLockKey = 7308061
+
+type postgresDriver struct{}
+
+func (postgresDriver) Name() string { return "postgres" }
+
+// EnsureDatabase connects to the postgres system DB and creates the target DB if missing.
+func (postgresDriver) EnsureDatabase(cfg *config.Config) error {
+	systemDSN := fmt.Sprintf("host=%s user=%s password=%s dbname=postgres port=%s sslmode=disable", cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBPort)
+	systemDB, err := sql.Open("postgres", systemDSN)
+	if err != nil {
+		return fmt.Errorf("system DB connection failed: %w", err)
+	}
+	defer systemDB.Close()
+
+	var exists bool
+	err = systemDB.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", cfg.DBName).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check DB existence: %w", err)
+	}
+
+	if !exists {
+		if _, err := systemDB.Exec("CREATE DATABASE " + cfg.DBName); err != nil {
+			return fmt.Errorf("failed to create DB %s: %w", cfg.DBName, err)
+		}
+		log.Printf("✅ Database '%s' created", cfg.DBName)
+	}
+	return nil
+}
+
+func (postgresDriver) Dialector(cfg *config.Config) gorm.Dialector {
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort)
+	return postgres.Open(dsn)
+}
+
+func (postgresDriver) Configure(*sql.DB) {}
+
+func (postgresDriver) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (postgresDriver) SchemaMigrationsDDL() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`
+}
+
+func (postgresDriver) Lock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey)
+	return err
+}
+
+func (postgresDriver) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+	return err
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go-crud-oapi/config"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDriver is a pure-Go embedded backend. DB_NAME is the database file,
+// or ":memory:" for a throwaway in-memory database.
+type sqliteDriver struct{}
+
+func (sqliteDriver) Name() string { return "sqlite" }
+
+func (sqliteDriver) EnsureDatabase(*config.Config) error { return nil }
+
+func (sqliteDriver) Dialector(cfg *config.Config) gorm.Dialector {
+	dsn := cfg.DBName
+	if dsn != ":memory:" && !strings.Contains(dsn, "?") {
+		dsn += "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	}
+	return sqlite.Open(dsn)
+}
+
+// Configure pins the pool to one connection: SQLite allows a single writer,
+// and every new connection to ":memory:" would otherwise see an empty database.
+func (sqliteDriver) Configure(sqlDB *sql.DB) {
+	sqlDB.SetMaxOpenConns(1)
+}
+
+func (sqliteDriver) Placeholder(int) string { return "?" }
+
+func (sqliteDriver) SchemaMigrationsDDL() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	)`
+}
+
+// Lock is a no-op: the database is embedded in a single process.
+func (sqliteDriver) Lock(context.Context, *sql.Conn) error { return nil }
+
+func (sqliteDriver) Unlock(context.Context, *sql.Conn) error { return nil }
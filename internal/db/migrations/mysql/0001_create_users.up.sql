@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS users (
+    id       BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name     VARCHAR(255),
+    email    VARCHAR(255),
+    phone    VARCHAR(32),
+    age      BIGINT,
+    role     VARCHAR(32),
+    password VARCHAR(255),
+    UNIQUE INDEX idx_users_email (email),
+    UNIQUE INDEX idx_users_phone (phone)
+);
@@ -0,0 +1,21 @@
+CREATE TABLE audit_logs (
+    id             BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    actor          VARCHAR(255) NOT NULL,
+    action         VARCHAR(64) NOT NULL,
+    target_user_id BIGINT UNSIGNED NOT NULL,
+    changes        TEXT,
+    request_id     VARCHAR(64),
+    created_at     DATETIME(3) NULL,
+    INDEX idx_audit_logs_actor (actor),
+    INDEX idx_audit_logs_action (action),
+    INDEX idx_audit_logs_target_user_id (target_user_id),
+    INDEX idx_audit_logs_created_at (created_at)
+);
+
+CREATE TRIGGER audit_logs_no_update
+    BEFORE UPDATE ON audit_logs
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+
+CREATE TRIGGER audit_logs_no_delete
+    BEFORE DELETE ON audit_logs
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
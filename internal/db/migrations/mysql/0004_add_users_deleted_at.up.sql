@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN deleted_at DATETIME(3) NULL, ADD INDEX idx_users_deleted_at (deleted_at);
@@ -0,0 +1,10 @@
+CREATE TABLE idempotency_records (
+    idempotency_key VARCHAR(512) PRIMARY KEY,
+    fingerprint     VARCHAR(64) NOT NULL,
+    status_code     BIGINT,
+    headers         TEXT,
+    body            LONGBLOB,
+    created_at      DATETIME(3) NULL,
+    expires_at      DATETIME(3) NOT NULL,
+    INDEX idx_idempotency_records_expires_at (expires_at)
+);
@@ -0,0 +1,14 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(255) NULL,
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_codes (
+    id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT UNSIGNED NOT NULL,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    INDEX idx_recovery_codes_user_id (user_id),
+    UNIQUE INDEX idx_recovery_codes_code_hash (code_hash)
+);
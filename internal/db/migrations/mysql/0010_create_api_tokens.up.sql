@@ -0,0 +1,17 @@
+ALTER TABLE users ADD COLUMN service_account BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE api_tokens (
+    id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id      BIGINT UNSIGNED NOT NULL,
+    name         VARCHAR(100) NOT NULL,
+    prefix       VARCHAR(16) NOT NULL,
+    token_hash   VARCHAR(64) NOT NULL,
+    scopes       VARCHAR(255) NOT NULL,
+    mfa          BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at   DATETIME(3) NULL,
+    last_used_at DATETIME(3) NULL,
+    revoked_at   DATETIME(3) NULL,
+    created_at   DATETIME(3) NULL,
+    INDEX idx_api_tokens_user_id (user_id),
+    UNIQUE INDEX idx_api_tokens_token_hash (token_hash)
+);
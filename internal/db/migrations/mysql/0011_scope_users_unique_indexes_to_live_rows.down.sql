@@ -0,0 +1,6 @@
+ALTER TABLE users
+    DROP INDEX idx_users_email,
+    DROP INDEX idx_users_phone,
+    DROP COLUMN live,
+    ADD UNIQUE INDEX idx_users_email (email),
+    ADD UNIQUE INDEX idx_users_phone (phone);
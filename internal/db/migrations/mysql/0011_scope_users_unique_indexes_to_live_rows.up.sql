@@ -0,0 +1,10 @@
+-- Soft-deleted users keep their email and phone, which must not block a new
+-- user from taking them. MySQL has no partial indexes, so the indexes also
+-- cover a column that is 1 for live rows and NULL, which never clashes, for
+-- deleted ones.
+ALTER TABLE users
+    DROP INDEX idx_users_email,
+    DROP INDEX idx_users_phone,
+    ADD COLUMN live TINYINT AS (IF(deleted_at IS NULL, 1, NULL)) VIRTUAL,
+    ADD UNIQUE INDEX idx_users_email (email, live),
+    ADD UNIQUE INDEX idx_users_phone (phone, live);
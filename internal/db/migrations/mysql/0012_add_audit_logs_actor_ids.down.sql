@@ -0,0 +1,4 @@
+ALTER TABLE audit_logs
+    DROP INDEX idx_audit_logs_actor_user_id,
+    DROP COLUMN actor_token_id,
+    DROP COLUMN actor_user_id;
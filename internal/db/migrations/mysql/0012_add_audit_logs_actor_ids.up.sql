@@ -0,0 +1,6 @@
+-- Emails change and are reused once a user is deleted, so the actor is
+-- recorded by ID. Older entries keep the email in actor.
+ALTER TABLE audit_logs
+    ADD COLUMN actor_user_id BIGINT UNSIGNED NULL,
+    ADD COLUMN actor_token_id BIGINT UNSIGNED NULL,
+    ADD INDEX idx_audit_logs_actor_user_id (actor_user_id);
@@ -0,0 +1,14 @@
+-- IF NOT EXISTS lets databases previously managed by GORM AutoMigrate adopt
+-- the versioned history without recreating their tables.
+CREATE TABLE IF NOT EXISTS users (
+    id       BIGSERIAL PRIMARY KEY,
+    name     TEXT,
+    email    TEXT,
+    phone    TEXT,
+    age      BIGINT,
+    role     TEXT,
+    password TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone ON users (phone);
@@ -0,0 +1,24 @@
+CREATE TABLE audit_logs (
+    id             BIGSERIAL PRIMARY KEY,
+    actor          TEXT NOT NULL,
+    action         TEXT NOT NULL,
+    target_user_id BIGINT NOT NULL,
+    changes        TEXT,
+    request_id     TEXT,
+    created_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_audit_logs_actor ON audit_logs (actor);
+CREATE INDEX idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX idx_audit_logs_target_user_id ON audit_logs (target_user_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
+
+CREATE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_records (
+    idempotency_key TEXT PRIMARY KEY,
+    fingerprint     TEXT NOT NULL,
+    status_code     BIGINT,
+    headers         TEXT,
+    body            BYTEA,
+    created_at      TIMESTAMPTZ,
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_idempotency_records_expires_at ON idempotency_records (expires_at);
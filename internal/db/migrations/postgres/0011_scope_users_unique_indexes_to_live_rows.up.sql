@@ -0,0 +1,6 @@
+-- Soft-deleted users keep their email and phone, which must not block a new
+-- user from taking them.
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_phone;
+CREATE UNIQUE INDEX idx_users_email ON users (email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_users_phone ON users (phone) WHERE deleted_at IS NULL;
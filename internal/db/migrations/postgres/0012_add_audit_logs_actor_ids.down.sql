@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_audit_logs_actor_user_id;
+ALTER TABLE audit_logs
+    DROP COLUMN actor_token_id,
+    DROP COLUMN actor_user_id;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS users (
+    id       INTEGER PRIMARY KEY AUTOINCREMENT,
+    name     TEXT,
+    email    TEXT,
+    phone    TEXT,
+    age      INTEGER,
+    role     TEXT,
+    password TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone ON users (phone);
@@ -0,0 +1,24 @@
+CREATE TABLE audit_logs (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor          TEXT NOT NULL,
+    action         TEXT NOT NULL,
+    target_user_id INTEGER NOT NULL,
+    changes        TEXT,
+    request_id     TEXT,
+    created_at     DATETIME
+);
+
+CREATE INDEX idx_audit_logs_actor ON audit_logs (actor);
+CREATE INDEX idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX idx_audit_logs_target_user_id ON audit_logs (target_user_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
+
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs
+BEGIN
+    SELECT RAISE(ABORT, 'audit_logs is append-only');
+END;
+
+CREATE TRIGGER audit_logs_no_delete BEFORE DELETE ON audit_logs
+BEGIN
+    SELECT RAISE(ABORT, 'audit_logs is append-only');
+END;
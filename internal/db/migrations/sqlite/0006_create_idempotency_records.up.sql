@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_records (
+    idempotency_key TEXT PRIMARY KEY,
+    fingerprint     TEXT NOT NULL,
+    status_code     INTEGER,
+    headers         TEXT,
+    body            BLOB,
+    created_at      DATETIME,
+    expires_at      DATETIME NOT NULL
+);
+
+CREATE INDEX idx_idempotency_records_expires_at ON idempotency_records (expires_at);
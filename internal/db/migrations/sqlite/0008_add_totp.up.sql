@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN totp_secret TEXT;
+ALTER TABLE users ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN totp_last_step INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_codes (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id    INTEGER NOT NULL,
+    code_hash  TEXT NOT NULL,
+    used_at    DATETIME,
+    created_at DATETIME
+);
+
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
+CREATE UNIQUE INDEX idx_recovery_codes_code_hash ON recovery_codes (code_hash);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_logs_actor_user_id;
+ALTER TABLE audit_logs DROP COLUMN actor_token_id;
+ALTER TABLE audit_logs DROP COLUMN actor_user_id;
@@ -0,0 +1,5 @@
+-- Emails change and are reused once a user is deleted, so the actor is
+-- recorded by ID. Older entries keep the email in actor.
+ALTER TABLE audit_logs ADD COLUMN actor_user_id INTEGER;
+ALTER TABLE audit_logs ADD COLUMN actor_token_id INTEGER;
+CREATE INDEX idx_audit_logs_actor_user_id ON audit_logs (actor_user_id);
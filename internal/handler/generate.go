@@ -0,0 +1,6 @@
+// Package handler holds the server interface generated from api/openapi.yaml.
+// The controllers implement it, so an operation added to or changed in the
+// spec does not compile until it is implemented to match.
+package handler
+
+//go:generate go tool oapi-codegen -config oapi-codegen.yaml ../../api/openapi.yaml
@@ -1,192 +1,2203 @@
 // Package handler provides primitives to interact with the openapi HTTP API.
 //
-// Code generated by github.com/deepmap/oapi-codegen version v1.16.3 DO NOT EDIT.
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
 package handler
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
-// User defines model for User.
-type User struct {
-	Email string `json:"email"`
-	Id    *int   `json:"id,omitempty"`
-	Name  string `json:"name"`
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AuditAction.
+const (
+	UserCreate         AuditAction = "user.create"
+	UserDelete         AuditAction = "user.delete"
+	UserEmailVerify    AuditAction = "user.email_verify"
+	UserMfaDisable     AuditAction = "user.mfa_disable"
+	UserMfaEnable      AuditAction = "user.mfa_enable"
+	UserPasswordChange AuditAction = "user.password_change"
+	UserPasswordReset  AuditAction = "user.password_reset"
+	UserPurge          AuditAction = "user.purge"
+	UserRestore        AuditAction = "user.restore"
+	UserTokenCreate    AuditAction = "user.token_create"
+	UserTokenRevoke    AuditAction = "user.token_revoke"
+	UserUnlock         AuditAction = "user.unlock"
+	UserUpdate         AuditAction = "user.update"
+)
+
+// Defines values for JSONPatchOp.
+const (
+	Add     JSONPatchOp = "add"
+	Copy    JSONPatchOp = "copy"
+	Move    JSONPatchOp = "move"
+	Remove  JSONPatchOp = "remove"
+	Replace JSONPatchOp = "replace"
+	Test    JSONPatchOp = "test"
+)
+
+// Defines values for JWKSKeysAlg.
+const (
+	EdDSA JWKSKeysAlg = "EdDSA"
+	RS256 JWKSKeysAlg = "RS256"
+)
+
+// Defines values for JWKSKeysKty.
+const (
+	OKP JWKSKeysKty = "OKP"
+	RSA JWKSKeysKty = "RSA"
+)
+
+// Defines values for MFAChallengeMfaRequired.
+const (
+	True MFAChallengeMfaRequired = true
+)
+
+// Defines values for Permission.
+const (
+	AuditRead        Permission = "audit:read"
+	TokensManage     Permission = "tokens:manage"
+	UsersDelete      Permission = "users:delete"
+	UsersRead        Permission = "users:read"
+	UsersReadPrivate Permission = "users:read_private"
+	UsersRestore     Permission = "users:restore"
+	UsersWrite       Permission = "users:write"
+)
+
+// Defines values for Role.
+const (
+	Admin  Role = "admin"
+	User   Role = "user"
+	Viewer Role = "viewer"
+)
+
+// Defines values for ListUsersParamsSort.
+const (
+	Age        ListUsersParamsSort = "age"
+	Email      ListUsersParamsSort = "email"
+	Id         ListUsersParamsSort = "id"
+	MinusAge   ListUsersParamsSort = "-age"
+	MinusEmail ListUsersParamsSort = "-email"
+	MinusId    ListUsersParamsSort = "-id"
+	MinusName  ListUsersParamsSort = "-name"
+	Name       ListUsersParamsSort = "name"
+)
+
+// APIToken defines model for APIToken.
+type APIToken struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Id         int        `json:"id"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name"`
+
+	// Prefix Start of the token, to tell tokens apart
+	Prefix    string       `json:"prefix"`
+	RevokedAt *time.Time   `json:"revoked_at,omitempty"`
+	Scopes    []Permission `json:"scopes"`
+}
+
+// APITokenCreated defines model for APITokenCreated.
+type APITokenCreated struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	Id         int        `json:"id"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name"`
+
+	// Prefix Start of the token, to tell tokens apart
+	Prefix    string       `json:"prefix"`
+	RevokedAt *time.Time   `json:"revoked_at,omitempty"`
+	Scopes    []Permission `json:"scopes"`
+	Token     string       `json:"token"`
+}
+
+// APITokenList defines model for APITokenList.
+type APITokenList struct {
+	Items []APIToken `json:"items"`
+}
+
+// AuditAction defines model for AuditAction.
+type AuditAction string
+
+// AuditLog defines model for AuditLog.
+type AuditLog struct {
+	Action AuditAction `json:"action"`
+
+	// Actor "user" for a signed-in caller, or "system" for background jobs and unauthenticated requests such as a password reset. Entries from before actor IDs were recorded hold the caller's email.
+	Actor string `json:"actor"`
+
+	// ActorTokenId ID of the API token the caller used, if any
+	ActorTokenId *int `json:"actor_token_id,omitempty"`
+
+	// ActorUserId ID of the signed-in caller
+	ActorUserId *int `json:"actor_user_id,omitempty"`
+
+	// Changes Changed fields, each with old and/or new values; secrets are redacted
+	Changes *map[string]struct {
+		New *interface{} `json:"new,omitempty"`
+		Old *interface{} `json:"old,omitempty"`
+	} `json:"changes,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	Id           int       `json:"id"`
+	RequestId    *string   `json:"request_id,omitempty"`
+	TargetUserId *int      `json:"target_user_id,omitempty"`
+}
+
+// AuditPage defines model for AuditPage.
+type AuditPage struct {
+	Items      []AuditLog `json:"items"`
+	NextCursor *string    `json:"next_cursor,omitempty"`
+}
+
+// AuthTokens defines model for AuthTokens.
+type AuthTokens struct {
+	// ExpiresIn Seconds until the access token expires
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+
+	// Token Access token, sent as "Authorization: Bearer <token>"
+	Token string `json:"token"`
+}
+
+// ChangePasswordRequest defines model for ChangePasswordRequest.
+type ChangePasswordRequest struct {
+	// NewPassword At least 8 characters and at most 72 bytes, with upper case, lower case and a digit
+	NewPassword string `json:"new_password"`
+	OldPassword string `json:"old_password"`
+}
+
+// CreateAPITokenRequest defines model for CreateAPITokenRequest.
+type CreateAPITokenRequest struct {
+	// ExpiresAt Omit for a token that is valid until revoked
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      string     `json:"name"`
+
+	// Scopes Permissions the token may use; each must be granted to the owner's role
+	Scopes []Permission `json:"scopes"`
+}
+
+// CreateServiceAccountRequest defines model for CreateServiceAccountRequest.
+type CreateServiceAccountRequest struct {
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+}
+
+// CreateUserRequest defines model for CreateUserRequest.
+type CreateUserRequest struct {
+	Age   *int                `json:"age,omitempty"`
+	Email openapi_types.Email `json:"email"`
+	Name  string              `json:"name"`
+
+	// Password At least 8 characters and at most 72 bytes, with upper case, lower case and a digit
+	Password string `json:"password"`
+
+	// Phone E.164 phone number, unique across users
+	Phone string `json:"phone"`
+	Role  Role   `json:"role"`
+}
+
+// FieldError defines model for FieldError.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Rule    string `json:"rule"`
+}
+
+// ForgotPasswordRequest defines model for ForgotPasswordRequest.
+type ForgotPasswordRequest struct {
+	Email openapi_types.Email `json:"email"`
+}
+
+// JSONPatch RFC 6902 JSON patch operations
+type JSONPatch = []struct {
+	From  *string      `json:"from,omitempty"`
+	Op    JSONPatchOp  `json:"op"`
+	Path  string       `json:"path"`
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// JSONPatchOp defines model for JSONPatch.Op.
+type JSONPatchOp string
+
+// JWKS defines model for JWKS.
+type JWKS struct {
+	Keys []struct {
+		Alg *JWKSKeysAlg `json:"alg,omitempty"`
+		Crv *string      `json:"crv,omitempty"`
+		E   *string      `json:"e,omitempty"`
+		Kid *string      `json:"kid,omitempty"`
+		Kty *JWKSKeysKty `json:"kty,omitempty"`
+		N   *string      `json:"n,omitempty"`
+		Use *string      `json:"use,omitempty"`
+		X   *string      `json:"x,omitempty"`
+	} `json:"keys"`
+}
+
+// JWKSKeysAlg defines model for JWKS.Keys.Alg.
+type JWKSKeysAlg string
+
+// JWKSKeysKty defines model for JWKS.Keys.Kty.
+type JWKSKeysKty string
+
+// LoginRequest defines model for LoginRequest.
+type LoginRequest struct {
+	Email    openapi_types.Email `json:"email"`
+	Password string              `json:"password"`
+}
+
+// LoginResult AuthTokens, or an MFAChallenge when mfa_required is true
+type LoginResult struct {
+	union json.RawMessage
+}
+
+// MFAChallenge defines model for MFAChallenge.
+type MFAChallenge struct {
+	ExpiresIn   int                     `json:"expires_in"`
+	MfaRequired MFAChallengeMfaRequired `json:"mfa_required"`
+
+	// MfaToken Short-lived token to send to /login/mfa with the second factor
+	MfaToken string `json:"mfa_token"`
+}
+
+// MFAChallengeMfaRequired defines model for MFAChallenge.MfaRequired.
+type MFAChallengeMfaRequired bool
+
+// MFACodeRequest defines model for MFACodeRequest.
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+// Permission defines model for Permission.
+type Permission string
+
+// Problem RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type Problem struct {
+	// Detail Explanation specific to this occurrence
+	Detail *string `json:"detail,omitempty"`
+
+	// Errors Per-field failures, on validation-failed problems and on invalid-request problems for requests that do not match this spec
+	Errors *[]FieldError `json:"errors,omitempty"`
+
+	// Field The request field that clashes, on conflict problems caused by a single field
+	Field *string `json:"field,omitempty"`
+
+	// Instance The request ID as a URN, to quote when reporting the problem
+	Instance   *string     `json:"instance,omitempty"`
+	Permission *Permission `json:"permission,omitempty"`
+
+	// Resource The kind of resource, on not-found and conflict problems
+	Resource *string `json:"resource,omitempty"`
+
+	// RetryAfter Seconds to wait before retrying, on 429 problems
+	RetryAfter *int `json:"retry_after,omitempty"`
+
+	// Status The HTTP status code
+	Status int `json:"status"`
+
+	// Title Short summary of the problem type
+	Title string `json:"title"`
+
+	// Type Identifies the kind of problem: /problems/invalid-request, /problems/validation-failed, /problems/unauthorized, /problems/forbidden, /problems/not-found, /problems/conflict, or about:blank when the status code says all there is to say. Relative references resolve against the API's base URL.
+	Type string `json:"type"`
+}
+
+// RecoveryCodes defines model for RecoveryCodes.
+type RecoveryCodes struct {
+	// RecoveryCodes Single-use codes that replace a TOTP code at /login/mfa
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// RefreshRequest defines model for RefreshRequest.
+type RefreshRequest struct {
+	RefreshToken *string `json:"refresh_token,omitempty"`
+}
+
+// ResetPasswordRequest defines model for ResetPasswordRequest.
+type ResetPasswordRequest struct {
+	// NewPassword At least 8 characters and at most 72 bytes, with upper case, lower case and a digit
+	NewPassword string `json:"new_password"`
+	Token       string `json:"token"`
+}
+
+// Role defines model for Role.
+type Role string
+
+// ServiceAccountList defines model for ServiceAccountList.
+type ServiceAccountList struct {
+	Items []UserFull `json:"items"`
+}
+
+// TOTPEnrollRequest defines model for TOTPEnrollRequest.
+type TOTPEnrollRequest struct {
+	Password string `json:"password"`
+}
+
+// TOTPEnrollment defines model for TOTPEnrollment.
+type TOTPEnrollment struct {
+	OtpauthUri string `json:"otpauth_uri"`
+
+	// QrCode data URI of a PNG QR code encoding otpauth_uri
+	QrCode string `json:"qr_code"`
+	Secret string `json:"secret"`
+}
+
+// UpdateMeRequest RFC 7386 JSON merge patch of the caller's own profile
+type UpdateMeRequest struct {
+	Name *string `json:"name,omitempty"`
+
+	// Phone E.164 phone number
+	Phone *string `json:"phone,omitempty"`
+}
+
+// UpdateUserRequest defines model for UpdateUserRequest.
+type UpdateUserRequest struct {
+	Age   *int                `json:"age,omitempty"`
+	Email openapi_types.Email `json:"email"`
+	Name  string              `json:"name"`
+
+	// Password Omit to keep the current password
+	Password *string `json:"password,omitempty"`
+
+	// Phone E.164 phone number, unique across users
+	Phone string `json:"phone"`
+	Role  Role   `json:"role"`
+}
+
+// UserFull Every field of a user except the password
+type UserFull struct {
+	Age           int        `json:"age"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified"`
+	Id            int        `json:"id"`
+
+	// LockedUntil Only present while the account is locked after failed logins
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	Name        string     `json:"name"`
+
+	// Phone Empty for service accounts
+	Phone          string `json:"phone"`
+	Role           Role   `json:"role"`
+	ServiceAccount bool   `json:"service_account"`
+
+	// Version Incremented on every write; the ETag is derived from it
+	Version int `json:"version"`
+}
+
+// UserMergePatch RFC 7386 JSON merge patch of the user
+type UserMergePatch struct {
+	Age      *int    `json:"age,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Password *string `json:"password,omitempty"`
+	Phone    *string `json:"phone,omitempty"`
+	Role     *Role   `json:"role,omitempty"`
+}
+
+// UserPage defines model for UserPage.
+type UserPage struct {
+	Items []UserView `json:"items"`
+
+	// NextCursor Pass as the cursor parameter to fetch the next page; absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// UserPublic Public view of a user
+type UserPublic struct {
+	// DeletedAt Only present for soft-deleted users returned with include_deleted
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Id        int        `json:"id"`
+	Name      string     `json:"name"`
+	Role      Role       `json:"role"`
+}
+
+// UserSummary Name-only view of a user given to viewers
+type UserSummary struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserView A user filtered to what the caller may see: UserFull for their own record or with users:read_private, UserSummary for viewers, and UserPublic for everyone else
+type UserView struct {
+	union json.RawMessage
+}
+
+// ValidationProblem defines model for ValidationProblem.
+type ValidationProblem struct {
+	// Detail Explanation specific to this occurrence
+	Detail *string `json:"detail,omitempty"`
+
+	// Errors Per-field failures, on validation-failed problems and on invalid-request problems for requests that do not match this spec
+	Errors []FieldError `json:"errors"`
+
+	// Field The request field that clashes, on conflict problems caused by a single field
+	Field *string `json:"field,omitempty"`
+
+	// Instance The request ID as a URN, to quote when reporting the problem
+	Instance   *string     `json:"instance,omitempty"`
+	Permission *Permission `json:"permission,omitempty"`
+
+	// Resource The kind of resource, on not-found and conflict problems
+	Resource *string `json:"resource,omitempty"`
+
+	// RetryAfter Seconds to wait before retrying, on 429 problems
+	RetryAfter *int `json:"retry_after,omitempty"`
+	Status     int  `json:"status"`
+
+	// Title Short summary of the problem type
+	Title string `json:"title"`
+
+	// Type Identifies the kind of problem: /problems/invalid-request, /problems/validation-failed, /problems/unauthorized, /problems/forbidden, /problems/not-found, /problems/conflict, or about:blank when the status code says all there is to say. Relative references resolve against the API's base URL.
+	Type string `json:"type"`
+}
+
+// VerifyMFARequest Send either code or recovery_code
+type VerifyMFARequest struct {
+	Code         *string `json:"code,omitempty"`
+	MfaToken     string  `json:"mfa_token"`
+	RecoveryCode *string `json:"recovery_code,omitempty"`
+}
+
+// Cursor defines model for Cursor.
+type Cursor = string
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// Limit defines model for Limit.
+type Limit = int
+
+// TokenID defines model for TokenID.
+type TokenID = int
+
+// UserID defines model for UserID.
+type UserID = int
+
+// BadRequest RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type BadRequest = Problem
+
+// Forbidden RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type Forbidden = Problem
+
+// NotFound RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type NotFound = Problem
+
+// PreconditionFailed RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type PreconditionFailed = Problem
+
+// PreconditionRequired RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type PreconditionRequired = Problem
+
+// TooManyRequests RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type TooManyRequests = Problem
+
+// Unauthorized RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type Unauthorized = Problem
+
+// UnsupportedMediaType RFC 7807 problem details. Members other than type, title, status, detail and instance are extensions carried by some problem types.
+type UnsupportedMediaType = Problem
+
+// ValidationFailed defines model for ValidationFailed.
+type ValidationFailed = ValidationProblem
+
+// ListAuditLogsParams defines parameters for ListAuditLogs.
+type ListAuditLogsParams struct {
+	// Limit Maximum number of items to return (default 20)
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque next_cursor value from the previous page
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// ActorUserId ID of the user who made the change
+	ActorUserId  *int         `form:"actor_user_id,omitempty" json:"actor_user_id,omitempty"`
+	Action       *AuditAction `form:"action,omitempty" json:"action,omitempty"`
+	TargetUserId *int         `form:"target_user_id,omitempty" json:"target_user_id,omitempty"`
+
+	// From Only entries created at or after this time
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only entries created before this time
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetMeParams defines parameters for GetMe.
+type GetMeParams struct {
+	// IfNoneMatch ETag from a previous read; returns 304 if the user is unchanged
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// PatchMeParams defines parameters for PatchMe.
+type PatchMeParams struct {
+	// IfMatch ETag from a previous read; the write fails with 412 if the user has changed since
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// ListUsersParams defines parameters for ListUsers.
+type ListUsersParams struct {
+	// Limit Maximum number of items to return (default 20)
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque next_cursor value from the previous page
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Role   *Role   `form:"role,omitempty" json:"role,omitempty"`
+	MinAge *int    `form:"min_age,omitempty" json:"min_age,omitempty"`
+	MaxAge *int    `form:"max_age,omitempty" json:"max_age,omitempty"`
+
+	// Email Case-insensitive substring match on email
+	Email *string `form:"email,omitempty" json:"email,omitempty"`
+
+	// Name Case-insensitive substring match on name
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// Sort Sort field, prefixed with '-' for descending order
+	Sort *ListUsersParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// IncludeDeleted Requires users:restore; also return soft-deleted users
+	IncludeDeleted *bool `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+}
+
+// ListUsersParamsSort defines parameters for ListUsers.
+type ListUsersParamsSort string
+
+// CreateUserParams defines parameters for CreateUser.
+type CreateUserParams struct {
+	// IdempotencyKey Client-chosen key that makes retries safe; a repeated request with the same key and body replays the original response with an Idempotent-Replayed header
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// DeleteUserParams defines parameters for DeleteUser.
+type DeleteUserParams struct {
+	// IfMatch ETag from a previous read; the write fails with 412 if the user has changed since
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetUserParams defines parameters for GetUser.
+type GetUserParams struct {
+	// IncludeDeleted Requires users:restore; also find a soft-deleted user
+	IncludeDeleted *bool `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+
+	// IfNoneMatch ETag from a previous read; returns 304 if the user is unchanged
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// PatchUserParams defines parameters for PatchUser.
+type PatchUserParams struct {
+	// IfMatch ETag from a previous read; the write fails with 412 if the user has changed since
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// UpdateUserParams defines parameters for UpdateUser.
+type UpdateUserParams struct {
+	// IfMatch ETag from a previous read; the write fails with 412 if the user has changed since
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// VerifyEmailParams defines parameters for VerifyEmail.
+type VerifyEmailParams struct {
+	Token string `form:"token" json:"token"`
+}
+
+// LoginJSONRequestBody defines body for Login for application/json ContentType.
+type LoginJSONRequestBody = LoginRequest
+
+// VerifyMFAJSONRequestBody defines body for VerifyMFA for application/json ContentType.
+type VerifyMFAJSONRequestBody = VerifyMFARequest
+
+// LogoutJSONRequestBody defines body for Logout for application/json ContentType.
+type LogoutJSONRequestBody = RefreshRequest
+
+// PatchMeApplicationJSONPatchPlusJSONRequestBody defines body for PatchMe for application/json-patch+json ContentType.
+type PatchMeApplicationJSONPatchPlusJSONRequestBody = JSONPatch
+
+// PatchMeApplicationMergePatchPlusJSONRequestBody defines body for PatchMe for application/merge-patch+json ContentType.
+type PatchMeApplicationMergePatchPlusJSONRequestBody = UpdateMeRequest
+
+// DisableTOTPJSONRequestBody defines body for DisableTOTP for application/json ContentType.
+type DisableTOTPJSONRequestBody = MFACodeRequest
+
+// EnrollTOTPJSONRequestBody defines body for EnrollTOTP for application/json ContentType.
+type EnrollTOTPJSONRequestBody = TOTPEnrollRequest
+
+// ConfirmTOTPJSONRequestBody defines body for ConfirmTOTP for application/json ContentType.
+type ConfirmTOTPJSONRequestBody = MFACodeRequest
+
+// ChangeMyPasswordJSONRequestBody defines body for ChangeMyPassword for application/json ContentType.
+type ChangeMyPasswordJSONRequestBody = ChangePasswordRequest
+
+// CreateMyTokenJSONRequestBody defines body for CreateMyToken for application/json ContentType.
+type CreateMyTokenJSONRequestBody = CreateAPITokenRequest
+
+// ForgotPasswordJSONRequestBody defines body for ForgotPassword for application/json ContentType.
+type ForgotPasswordJSONRequestBody = ForgotPasswordRequest
+
+// ResetPasswordJSONRequestBody defines body for ResetPassword for application/json ContentType.
+type ResetPasswordJSONRequestBody = ResetPasswordRequest
+
+// CreateServiceAccountJSONRequestBody defines body for CreateServiceAccount for application/json ContentType.
+type CreateServiceAccountJSONRequestBody = CreateServiceAccountRequest
+
+// RefreshTokenJSONRequestBody defines body for RefreshToken for application/json ContentType.
+type RefreshTokenJSONRequestBody = RefreshRequest
+
+// CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
+type CreateUserJSONRequestBody = CreateUserRequest
+
+// PatchUserApplicationJSONPatchPlusJSONRequestBody defines body for PatchUser for application/json-patch+json ContentType.
+type PatchUserApplicationJSONPatchPlusJSONRequestBody = JSONPatch
+
+// PatchUserApplicationMergePatchPlusJSONRequestBody defines body for PatchUser for application/merge-patch+json ContentType.
+type PatchUserApplicationMergePatchPlusJSONRequestBody = UserMergePatch
+
+// UpdateUserJSONRequestBody defines body for UpdateUser for application/json ContentType.
+type UpdateUserJSONRequestBody = UpdateUserRequest
+
+// ChangePasswordJSONRequestBody defines body for ChangePassword for application/json ContentType.
+type ChangePasswordJSONRequestBody = ChangePasswordRequest
+
+// CreateUserTokenJSONRequestBody defines body for CreateUserToken for application/json ContentType.
+type CreateUserTokenJSONRequestBody = CreateAPITokenRequest
+
+// AsAuthTokens returns the union data inside the LoginResult as a AuthTokens
+func (t LoginResult) AsAuthTokens() (AuthTokens, error) {
+	var body AuthTokens
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromAuthTokens overwrites any union data inside the LoginResult as the provided AuthTokens
+func (t *LoginResult) FromAuthTokens(v AuthTokens) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeAuthTokens performs a merge with any union data inside the LoginResult, using the provided AuthTokens
+func (t *LoginResult) MergeAuthTokens(v AuthTokens) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsMFAChallenge returns the union data inside the LoginResult as a MFAChallenge
+func (t LoginResult) AsMFAChallenge() (MFAChallenge, error) {
+	var body MFAChallenge
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromMFAChallenge overwrites any union data inside the LoginResult as the provided MFAChallenge
+func (t *LoginResult) FromMFAChallenge(v MFAChallenge) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeMFAChallenge performs a merge with any union data inside the LoginResult, using the provided MFAChallenge
+func (t *LoginResult) MergeMFAChallenge(v MFAChallenge) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t LoginResult) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *LoginResult) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
+// AsUserFull returns the union data inside the UserView as a UserFull
+func (t UserView) AsUserFull() (UserFull, error) {
+	var body UserFull
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromUserFull overwrites any union data inside the UserView as the provided UserFull
+func (t *UserView) FromUserFull(v UserFull) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeUserFull performs a merge with any union data inside the UserView, using the provided UserFull
+func (t *UserView) MergeUserFull(v UserFull) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsUserPublic returns the union data inside the UserView as a UserPublic
+func (t UserView) AsUserPublic() (UserPublic, error) {
+	var body UserPublic
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromUserPublic overwrites any union data inside the UserView as the provided UserPublic
+func (t *UserView) FromUserPublic(v UserPublic) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeUserPublic performs a merge with any union data inside the UserView, using the provided UserPublic
+func (t *UserView) MergeUserPublic(v UserPublic) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsUserSummary returns the union data inside the UserView as a UserSummary
+func (t UserView) AsUserSummary() (UserSummary, error) {
+	var body UserSummary
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromUserSummary overwrites any union data inside the UserView as the provided UserSummary
+func (t *UserView) FromUserSummary(v UserSummary) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeUserSummary performs a merge with any union data inside the UserView, using the provided UserSummary
+func (t *UserView) MergeUserSummary(v UserSummary) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t UserView) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *UserView) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (GET /.well-known/jwks.json)
+	GetJWKS(w http.ResponseWriter, r *http.Request)
+
+	// (GET /audit)
+	ListAuditLogs(w http.ResponseWriter, r *http.Request, params ListAuditLogsParams)
+
+	// (POST /login)
+	Login(w http.ResponseWriter, r *http.Request)
+
+	// (POST /login/mfa)
+	VerifyMFA(w http.ResponseWriter, r *http.Request)
+
+	// (POST /logout)
+	Logout(w http.ResponseWriter, r *http.Request)
+
+	// (GET /me)
+	GetMe(w http.ResponseWriter, r *http.Request, params GetMeParams)
+
+	// (PATCH /me)
+	PatchMe(w http.ResponseWriter, r *http.Request, params PatchMeParams)
+
+	// (DELETE /me/mfa/totp)
+	DisableTOTP(w http.ResponseWriter, r *http.Request)
+
+	// (POST /me/mfa/totp)
+	EnrollTOTP(w http.ResponseWriter, r *http.Request)
+
+	// (POST /me/mfa/totp/verify)
+	ConfirmTOTP(w http.ResponseWriter, r *http.Request)
+
+	// (POST /me/password)
+	ChangeMyPassword(w http.ResponseWriter, r *http.Request)
+
+	// (GET /me/tokens)
+	ListMyTokens(w http.ResponseWriter, r *http.Request)
+
+	// (POST /me/tokens)
+	CreateMyToken(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /me/tokens/{tokenID})
+	RevokeMyToken(w http.ResponseWriter, r *http.Request, tokenID TokenID)
+
+	// (POST /me/verify-email)
+	ResendVerification(w http.ResponseWriter, r *http.Request)
+
+	// (POST /password/forgot)
+	ForgotPassword(w http.ResponseWriter, r *http.Request)
+
+	// (POST /password/reset)
+	ResetPassword(w http.ResponseWriter, r *http.Request)
+
+	// (GET /service-accounts)
+	ListServiceAccounts(w http.ResponseWriter, r *http.Request)
+
+	// (POST /service-accounts)
+	CreateServiceAccount(w http.ResponseWriter, r *http.Request)
+
+	// (POST /token/refresh)
+	RefreshToken(w http.ResponseWriter, r *http.Request)
+
+	// (GET /users)
+	ListUsers(w http.ResponseWriter, r *http.Request, params ListUsersParams)
+
+	// (POST /users)
+	CreateUser(w http.ResponseWriter, r *http.Request, params CreateUserParams)
+
+	// (DELETE /users/{id})
+	DeleteUser(w http.ResponseWriter, r *http.Request, id UserID, params DeleteUserParams)
+
+	// (GET /users/{id})
+	GetUser(w http.ResponseWriter, r *http.Request, id UserID, params GetUserParams)
+
+	// (PATCH /users/{id})
+	PatchUser(w http.ResponseWriter, r *http.Request, id UserID, params PatchUserParams)
+
+	// (PUT /users/{id})
+	UpdateUser(w http.ResponseWriter, r *http.Request, id UserID, params UpdateUserParams)
+
+	// (POST /users/{id}/password)
+	ChangePassword(w http.ResponseWriter, r *http.Request, id UserID)
+
+	// (POST /users/{id}/restore)
+	RestoreUser(w http.ResponseWriter, r *http.Request, id UserID)
+
+	// (GET /users/{id}/tokens)
+	ListUserTokens(w http.ResponseWriter, r *http.Request, id UserID)
+
+	// (POST /users/{id}/tokens)
+	CreateUserToken(w http.ResponseWriter, r *http.Request, id UserID)
+
+	// (DELETE /users/{id}/tokens/{tokenID})
+	RevokeUserToken(w http.ResponseWriter, r *http.Request, id UserID, tokenID TokenID)
+
+	// (POST /users/{id}/unlock)
+	UnlockUser(w http.ResponseWriter, r *http.Request, id UserID)
+
+	// (GET /verify-email)
+	VerifyEmail(w http.ResponseWriter, r *http.Request, params VerifyEmailParams)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// (GET /.well-known/jwks.json)
+func (_ Unimplemented) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /audit)
+func (_ Unimplemented) ListAuditLogs(w http.ResponseWriter, r *http.Request, params ListAuditLogsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /login)
+func (_ Unimplemented) Login(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /login/mfa)
+func (_ Unimplemented) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /logout)
+func (_ Unimplemented) Logout(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /me)
+func (_ Unimplemented) GetMe(w http.ResponseWriter, r *http.Request, params GetMeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PATCH /me)
+func (_ Unimplemented) PatchMe(w http.ResponseWriter, r *http.Request, params PatchMeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /me/mfa/totp)
+func (_ Unimplemented) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /me/mfa/totp)
+func (_ Unimplemented) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /me/mfa/totp/verify)
+func (_ Unimplemented) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /me/password)
+func (_ Unimplemented) ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /me/tokens)
+func (_ Unimplemented) ListMyTokens(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /me/tokens)
+func (_ Unimplemented) CreateMyToken(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /me/tokens/{tokenID})
+func (_ Unimplemented) RevokeMyToken(w http.ResponseWriter, r *http.Request, tokenID TokenID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /me/verify-email)
+func (_ Unimplemented) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /password/forgot)
+func (_ Unimplemented) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /password/reset)
+func (_ Unimplemented) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /service-accounts)
+func (_ Unimplemented) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /service-accounts)
+func (_ Unimplemented) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /token/refresh)
+func (_ Unimplemented) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /users)
+func (_ Unimplemented) ListUsers(w http.ResponseWriter, r *http.Request, params ListUsersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /users)
+func (_ Unimplemented) CreateUser(w http.ResponseWriter, r *http.Request, params CreateUserParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /users/{id})
+func (_ Unimplemented) DeleteUser(w http.ResponseWriter, r *http.Request, id UserID, params DeleteUserParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /users/{id})
+func (_ Unimplemented) GetUser(w http.ResponseWriter, r *http.Request, id UserID, params GetUserParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PATCH /users/{id})
+func (_ Unimplemented) PatchUser(w http.ResponseWriter, r *http.Request, id UserID, params PatchUserParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (PUT /users/{id})
+func (_ Unimplemented) UpdateUser(w http.ResponseWriter, r *http.Request, id UserID, params UpdateUserParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /users/{id}/password)
+func (_ Unimplemented) ChangePassword(w http.ResponseWriter, r *http.Request, id UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /users/{id}/restore)
+func (_ Unimplemented) RestoreUser(w http.ResponseWriter, r *http.Request, id UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /users/{id}/tokens)
+func (_ Unimplemented) ListUserTokens(w http.ResponseWriter, r *http.Request, id UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /users/{id}/tokens)
+func (_ Unimplemented) CreateUserToken(w http.ResponseWriter, r *http.Request, id UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /users/{id}/tokens/{tokenID})
+func (_ Unimplemented) RevokeUserToken(w http.ResponseWriter, r *http.Request, id UserID, tokenID TokenID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /users/{id}/unlock)
+func (_ Unimplemented) UnlockUser(w http.ResponseWriter, r *http.Request, id UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /verify-email)
+func (_ Unimplemented) VerifyEmail(w http.ResponseWriter, r *http.Request, params VerifyEmailParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetJWKS operation middleware
+func (siw *ServerInterfaceWrapper) GetJWKS(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetJWKS(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAuditLogs operation middleware
+func (siw *ServerInterfaceWrapper) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditLogsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "actor_user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor_user_id", r.URL.Query(), &params.ActorUserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "actor_user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", r.URL.Query(), &params.Action)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "action", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "target_user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "target_user_id", r.URL.Query(), &params.TargetUserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "target_user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAuditLogs(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Login operation middleware
+func (siw *ServerInterfaceWrapper) Login(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Login(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// VerifyMFA operation middleware
+func (siw *ServerInterfaceWrapper) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.VerifyMFA(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// Logout operation middleware
+func (siw *ServerInterfaceWrapper) Logout(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Logout(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetMe operation middleware
+func (siw *ServerInterfaceWrapper) GetMe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMeParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMe(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PatchMe operation middleware
+func (siw *ServerInterfaceWrapper) PatchMe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchMeParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchMe(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DisableTOTP operation middleware
+func (siw *ServerInterfaceWrapper) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DisableTOTP(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// EnrollTOTP operation middleware
+func (siw *ServerInterfaceWrapper) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.EnrollTOTP(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ConfirmTOTP operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ConfirmTOTP(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ChangeMyPassword operation middleware
+func (siw *ServerInterfaceWrapper) ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ChangeMyPassword(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListMyTokens operation middleware
+func (siw *ServerInterfaceWrapper) ListMyTokens(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListMyTokens(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateMyToken operation middleware
+func (siw *ServerInterfaceWrapper) CreateMyToken(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateMyToken(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeMyToken operation middleware
+func (siw *ServerInterfaceWrapper) RevokeMyToken(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "tokenID" -------------
+	var tokenID TokenID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tokenID", chi.URLParam(r, "tokenID"), &tokenID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tokenID", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeMyToken(w, r, tokenID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ResendVerification operation middleware
+func (siw *ServerInterfaceWrapper) ResendVerification(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResendVerification(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ForgotPassword operation middleware
+func (siw *ServerInterfaceWrapper) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ForgotPassword(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ResetPassword operation middleware
+func (siw *ServerInterfaceWrapper) ResetPassword(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResetPassword(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListServiceAccounts operation middleware
+func (siw *ServerInterfaceWrapper) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListServiceAccounts(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateServiceAccount operation middleware
+func (siw *ServerInterfaceWrapper) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateServiceAccount(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RefreshToken operation middleware
+func (siw *ServerInterfaceWrapper) RefreshToken(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RefreshToken(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListUsers operation middleware
+func (siw *ServerInterfaceWrapper) ListUsers(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListUsersParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "role" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "role", r.URL.Query(), &params.Role)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "role", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "min_age" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_age", r.URL.Query(), &params.MinAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "min_age", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "max_age" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_age", r.URL.Query(), &params.MaxAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "max_age", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "email" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "email", r.URL.Query(), &params.Email)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "email", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "name", r.URL.Query(), &params.Name)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListUsers(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateUser operation middleware
+func (siw *ServerInterfaceWrapper) CreateUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateUserParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateUser(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteUser operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteUserParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUser(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUser operation middleware
+func (siw *ServerInterfaceWrapper) GetUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUserParams
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUser(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-// CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
-type CreateUserJSONRequestBody = User
+// PatchUser operation middleware
+func (siw *ServerInterfaceWrapper) PatchUser(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-// UpdateUserJSONRequestBody defines body for UpdateUser for application/json ContentType.
-type UpdateUserJSONRequestBody = User
+	ctx := r.Context()
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-	// (GET /users)
-	ListUsers(w http.ResponseWriter, r *http.Request)
+	r = r.WithContext(ctx)
 
-	// (POST /users)
-	CreateUser(w http.ResponseWriter, r *http.Request)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchUserParams
 
-	// (DELETE /users/{id})
-	DeleteUser(w http.ResponseWriter, r *http.Request, id int)
+	headers := r.Header
 
-	// (GET /users/{id})
-	GetUser(w http.ResponseWriter, r *http.Request, id int)
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
 
-	// (PUT /users/{id})
-	UpdateUser(w http.ResponseWriter, r *http.Request, id int)
-}
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
 
-// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+		params.IfMatch = &IfMatch
 
-type Unimplemented struct{}
+	}
 
-// (GET /users)
-func (_ Unimplemented) ListUsers(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchUser(w, r, id, params)
+	}))
 
-// (POST /users)
-func (_ Unimplemented) CreateUser(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// (DELETE /users/{id})
-func (_ Unimplemented) DeleteUser(w http.ResponseWriter, r *http.Request, id int) {
-	w.WriteHeader(http.StatusNotImplemented)
+	handler.ServeHTTP(w, r)
 }
 
-// (GET /users/{id})
-func (_ Unimplemented) GetUser(w http.ResponseWriter, r *http.Request, id int) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// UpdateUser operation middleware
+func (siw *ServerInterfaceWrapper) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
-// (PUT /users/{id})
-func (_ Unimplemented) UpdateUser(w http.ResponseWriter, r *http.Request, id int) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	var err error
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
-}
+	// ------------- Path parameter "id" -------------
+	var id UserID
 
-type MiddlewareFunc func(http.Handler) http.Handler
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-// ListUsers operation middleware
-func (siw *ServerInterfaceWrapper) ListUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateUserParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListUsers(w, r)
+		siw.Handler.UpdateUser(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		handler = middleware(handler)
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	handler.ServeHTTP(w, r)
 }
 
-// CreateUser operation middleware
-func (siw *ServerInterfaceWrapper) CreateUser(w http.ResponseWriter, r *http.Request) {
+// ChangePassword operation middleware
+func (siw *ServerInterfaceWrapper) ChangePassword(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateUser(w, r)
+		siw.Handler.ChangePassword(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		handler = middleware(handler)
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	handler.ServeHTTP(w, r)
 }
 
-// DeleteUser operation middleware
-func (siw *ServerInterfaceWrapper) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// RestoreUser operation middleware
+func (siw *ServerInterfaceWrapper) RestoreUser(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
 	// ------------- Path parameter "id" -------------
-	var id int
+	var id UserID
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteUser(w, r, id)
+		siw.Handler.RestoreUser(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		handler = middleware(handler)
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	handler.ServeHTTP(w, r)
 }
 
-// GetUser operation middleware
-func (siw *ServerInterfaceWrapper) GetUser(w http.ResponseWriter, r *http.Request) {
+// ListUserTokens operation middleware
+func (siw *ServerInterfaceWrapper) ListUserTokens(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListUserTokens(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateUserToken operation middleware
+func (siw *ServerInterfaceWrapper) CreateUserToken(w http.ResponseWriter, r *http.Request) {
+
 	var err error
 
 	// ------------- Path parameter "id" -------------
-	var id int
+	var id UserID
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetUser(w, r, id)
+		siw.Handler.CreateUserToken(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		handler = middleware(handler)
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	handler.ServeHTTP(w, r)
 }
 
-// UpdateUser operation middleware
-func (siw *ServerInterfaceWrapper) UpdateUser(w http.ResponseWriter, r *http.Request) {
+// RevokeUserToken operation middleware
+func (siw *ServerInterfaceWrapper) RevokeUserToken(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "tokenID" -------------
+	var tokenID TokenID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tokenID", chi.URLParam(r, "tokenID"), &tokenID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tokenID", Err: err})
+		return
+	}
+
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeUserToken(w, r, id, tokenID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UnlockUser operation middleware
+func (siw *ServerInterfaceWrapper) UnlockUser(w http.ResponseWriter, r *http.Request) {
+
 	var err error
 
 	// ------------- Path parameter "id" -------------
-	var id int
+	var id UserID
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UnlockUser(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// VerifyEmail operation middleware
+func (siw *ServerInterfaceWrapper) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params VerifyEmailParams
+
+	// ------------- Required query parameter "token" -------------
+
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateUser(w, r, id)
+		siw.Handler.VerifyEmail(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		handler = middleware(handler)
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	handler.ServeHTTP(w, r)
 }
 
 type UnescapedCookieParamError struct {
@@ -302,6 +2313,66 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/.well-known/jwks.json", wrapper.GetJWKS)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/audit", wrapper.ListAuditLogs)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/login", wrapper.Login)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/login/mfa", wrapper.VerifyMFA)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/logout", wrapper.Logout)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/me", wrapper.GetMe)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/me", wrapper.PatchMe)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/me/mfa/totp", wrapper.DisableTOTP)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/me/mfa/totp", wrapper.EnrollTOTP)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/me/mfa/totp/verify", wrapper.ConfirmTOTP)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/me/password", wrapper.ChangeMyPassword)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/me/tokens", wrapper.ListMyTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/me/tokens", wrapper.CreateMyToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/me/tokens/{tokenID}", wrapper.RevokeMyToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/me/verify-email", wrapper.ResendVerification)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/password/forgot", wrapper.ForgotPassword)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/password/reset", wrapper.ResetPassword)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/service-accounts", wrapper.ListServiceAccounts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/service-accounts", wrapper.CreateServiceAccount)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/token/refresh", wrapper.RefreshToken)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/users", wrapper.ListUsers)
 	})
@@ -314,9 +2385,230 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/users/{id}", wrapper.GetUser)
 	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/users/{id}", wrapper.PatchUser)
+	})
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/users/{id}", wrapper.UpdateUser)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/{id}/password", wrapper.ChangePassword)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/{id}/restore", wrapper.RestoreUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/users/{id}/tokens", wrapper.ListUserTokens)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/{id}/tokens", wrapper.CreateUserToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/users/{id}/tokens/{tokenID}", wrapper.RevokeUserToken)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/users/{id}/unlock", wrapper.UnlockUser)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/verify-email", wrapper.VerifyEmail)
+	})
 
 	return r
 }
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+w9aXfbNrZ/BYdvzsnMGUpekrSN8ymTpZM2Tj22036o83wg8kpCTQEsAFrWy/F/fwcX",
+	"AAmKoBZvWaafEovEdnH3jZ+STMxKwYFrlRx8SqZAc5D439endGL+zUFlkpWaCZ4cJCdaCj4hwDXTC6Lp",
+	"hIgx0VMglQL5SJGskhK4JpcglRmRJiqbwoyamfSihOQgUVoyPkmur9PkbQ6zUmjg+hjKgi4gj6wImmhB",
+	"zhItKzhLyHwKHFeUoErBFRCmCCUSJzC7oZwAlQUDSST8WYHSZM70FMcoOgNSr5otBj/DYs0ej0HLxYux",
+	"BhnbWyZ4rsz+5pRpMoKxkGZnWi7M+MjMjGuYgEyuzdwllXQG2kH8ZSWViKzyS0n/rIBwuNLnGb5DLmlR",
+	"ARlLMcNjlRIumagUKekEkjRhZtifFUhzOE5nZmE7ctMLyRYGMp2tvCwYcD3IpkIBJxewIHpKNZnRC1B4",
+	"bAaKKDqG5/ZKgGrIe67BjKY8JyORL9z1KXwqJJswTovmhnEg5c3N6YFHGGJR9oz7Y9u/m3Ovuu0ZvXoH",
+	"fKKnycH+06dpDCDjQ6qzaRcShjzsBdAG/BJo/hzPMJdMAxlTVii7+yd7+4Q1pEKmVJFsSvkEcqIYz6B3",
+	"/+OB3cKamxu/Fxy236wEXUmuyOPdJ639MUUq7ja4Ymtm0Y32947NmO7u7JBesVk1I7yajUAa8mUaZkhR",
+	"dmfk7zmMaVVosr/7jx7ULnDupYs10yYHe7u7aTJj3P2VdskwTU7FBfC3r8w4nL2ketpMrt3TNDFozKTh",
+	"UYYVraTtNPmgQPbOyfLtprtOE08LyCn+RfNjS1Pmr0xwDRz/S8uyYBk1wN0ppRgVMPvnH8pA+lMw/98k",
+	"jJOD5H92Gua/Y5+qnSM7yi7avqvTKSCxpgaLPOciQhLqiNAgzYwWYyFnkJsHuQBFuDAMQmeG9JkiqoTs",
+	"OQEphVSkYEorAjSbkksmCtz4GU+u0+SNkCOW58Af+oQZLQqQpKDZhZUr9pZICXLGlBFqqaGQ3PCjF0dv",
+	"CeKHkUoSCCWFmDBOFOCLBh5+fGrgMauUJmZLBWggei4GY5ppIQlwKYpiBlzb078X+o2oeP7Qh5egRCUz",
+	"aG4OrpjSZktHEoUdM2+/oayAB92c54JdlIJa50CNZWmnxzWRfYa9BtdPRguH/iAvQZJRpcmc2pMosynk",
+	"Q+KQ8oWjbPWgdy8EmVG+IFRrmJVaed2CKfL2CHF3LKT9G2aUFYTmuQSlnltlh1CjIBHUlQZWWUpDZTJ8",
+	"0LNL9/ZOoG/hRj9wWumpkOz/4MHJYQRUgnQkbpibYQB8khLGL2nB8pTAVYnXKySRcCkuIE9wz6oqSyE1",
+	"5IeQM3qKDP3h9v7SrjMw6xJmsYySek+kRPQ0fJoi5v1qDkPvkbKbBdYyIKsqjnEfRHBAvikkkMt6DiKr",
+	"AhSKWbeAWf/F0VuU5Ob/pRQlSM2suMwk6qHnFE/jjn2Q5FTDQLMZJB3NL03sxaqtxrA8Jr3TpKBKnxuZ",
+	"sdVsVlf41H1QShizq5htRqX29hjibGr0KA1FYf9UhJZU6thiDnm32qDKRGkBjDrbWnytBWhyXc9GpaSL",
+	"xCo4nlP/bvUjPH992nq5NLzOj/VEYvQHZIjMHg9e2tcQgYvil3Fy8PvqDfqByXW6jEHaIxZcUSO9rUZ3",
+	"vj/7z9Wz4XCYxEyH8EB2fHe3H4P9vmNWnWuvXIN2IxgHR1gDYZwtCr4qZ/pFZnHqUwLcKM6/J5UCObSQ",
+	"T1L7V1XmwV85FND8VVKl5kLm59aE8D9LUFrI5q1KNs8qXojswv81G9Nz4HRUQPhLzlT4U72KBAXa/4oC",
+	"6vwSJBsv/G94Aeft/dvfLOYnHztX6EDxTky6t0Jr+Ky8jQCU12mCql6XbM9wN2cJSlhKFJtwyAeMO00U",
+	"he9ZohZKw8y9NaLZxUQaBZH8IUYKzegK5SRwzbLQ6FZEVdmUUIVKuwUXQXANyWtuDXaU9c53YfXRt68U",
+	"mQO6MjIhc2NmiyK32hbu6pHTA4ZoeHcgh7OcWwCziFPn7SvPpxoVupkc9evUmKOUL5I0wlHt/AZua6Zf",
+	"hmZ0Nouj9mJzqzfS4qh14e3r5zBPDj5dp4kozOrX1xE6WpLIztQfMyhylVqTBx0DBq6U5ztCEg5z69lR",
+	"z4mCTIJWhOIl5DTTaId3lrmJbOuTUw5lzlvPm2GaygnoEOoRu7fDxi3Wp55k1rNvQzRHdAK35oWeeju8",
+	"ME0CX1rcXbEpr9RT5LcRHPEKBOP9fsOKa1YgntIsA6UcJbihUVyVMJagpue1ROpek3/SXvNFsEKKNodh",
+	"CmfJC6ddo2Z1QP5lVd6zanf3cYYv43/hLNlQyC1vMQ0hEQOiJY0jx5sCv0aH5s49A4ucTpMCqNLkB+NV",
+	"k4ZcpOWLxj0plCbf75PRQoNKLdlVZQmSZFRBSgoxd/+3I0jOJuhQqsmpXjhCTaLIWxvbYNAS4FozpO2T",
+	"RiGGFORlfS/E2irskk95xrQTOJ77Um1MBdSyHWJ6iybdUmMN3Krof1uhObZ31aiIqlFiyYwujER4bpkm",
+	"+lBGQCaSciPntMBXxZyjVJIC1YMbaKQzxt/aUXtrlCenmbpT9N/QCchLlsGLLBMV1733FAHbU+u19H8+",
+	"jmns5qBrDnhs3unZPY7v37vxX/bu2HHnxsv6OPSy7sb4FmoLLfKwv2yGRhvA44vkDeVUcIj444d73z0h",
+	"+NB5vlNScWaiPDSTQin0waskDeyNf+492Xv69OnT3b3d3eQe8MFfh92ymy8AawxV3hht5rWUQnZxBDWd",
+	"JZPJTd3Z+wyUcjjVPVdVQHsa2PvuyVqeapd3w5sVoqcQciL0WgG0MQYv7cS+FVv4p5Nf3h/FIzbHb16S",
+	"757t7hPzjvPXmM2glFYhg1uCuhSzKBhFGVpzNEfYwExc4l2bYBqGoNwPmShR8Qalo5YRhjNiy6D66lTi",
+	"logrEzcqBohl9eyn334+6Z7tAhZt7a/9mBaT8IjHJ/tPv0vS5HX+6uRF9BCZvIyeIY6HFz1a8YVetJd9",
+	"kaTJLz8fRZeMa2yVii95FddN14BvCfIItRjQ35lQxe2xvc15t1d+ar6zitG4vaqqiOgyjRaOtjLl5PDN",
+	"i5dTY+/xCdiEAeM88KsaNUfLCpI0ERw2cQzVC6BraNWr4crWvdP6ZY2V0BWb4b4DNDPbb+A0EqIAyv37",
+	"PQbAyVRIPSjYJeROsdKCKODmL7KDgaud2ZgGUXq0UsjYW3Cr77G103Ajaw0AAyGRQy8qZiK3kKNagzRH",
+	"+d/fdwfPPn767vpva7eFg2OrBsrfkptLHUigeZIGf5yXkl02riN1gPH9+q+W70sdNF4uamxQPx2CQx3M",
+	"KG9LooaSvHM8Kg6+/2H3e+Lc7yQHbXILhuQQjPqgiNBTMAEayomZNiWa6QJSojTVlUrdANRhGFea8gzQ",
+	"twBXGrjVuDMqJbOhKiVmUK9l5lPW0dO+GDtnRL25KgvKratelZCxMcusns4UEZmN16G4WdINCC0MrBaE",
+	"caMBRf3yGDiOmg4DlPkYOqikUeYED4IGAxdScKeyCqDgPpQz8JGH+vlYyMaJhuZRLmLBbATMRvZGoC5F",
+	"hF6tMK2IiuABcS9ZQdXUHTITfFywLNh7RjFEPVqgQ5FPCiBeIdpAHfMIsnozb19Zr+KH4/cYZ/izEtpx",
+	"WwmlkJrxictOslgdrl1JflBVLD94OtqF7+kzGOyPn2SDJ/nuePAsf/p0sJftZY/He+NnsE+jQqdFvptb",
+	"eT7CHT/bBTNIMa7j4AheLvRgjM5WgzIdYLfPpUDG4ytaLs7pjdLIcBNP9p+FK3YlhSX0+LH+fXp65DgB",
+	"QYYYbPnJ7rPYdMg+eoQIUdVsRuXCO1lDRtGCxrEDYw20GGj0oows9DYHrtmYgfUE+ItxSx0QH4hUO0sE",
+	"nAaPOtQfPqyCwHL4+9hnnoQ/1jgQ/uhPZdWOkaj0waig/KLJUQxgTpTJb6MF+vskhmWN/KWLITkGk/ly",
+	"aW58DMgaFWJgcQmETqghR+8rf6TIiCogH47fWY7cALu7r/UeO3th9q5rFIpJy2PIxCXIhRHUEd1busfn",
+	"mX++hDbIgwaVAoSF46fO7CCUnP5yemShRHWgi4SMtQdverTepQ3Fj4Ruyl61Y52n9To6pwL99boyVxw1",
+	"5uhd66g8dj6JxuycMe7UpCRNLhnMQUY1obbr7C5Co8ah9aYqiluERg2WvsZMrd6rvZ0ttBKYzeoz4JGl",
+	"hS4NQzuvJIvSy5/y3GvSbYTLqTZS/C0mT5Oj9z+S/xxbYgSeidyI8XDuyG3ZYNV6xHHvpUl7Pr+z2Kk/",
+	"YJD5MLQOIqrx4x++s56SGcgJeH/JuB2wFHNu5MeYWcfWHfhgN3bvRW++56zfkOcVowxakAuAspWqF1DE",
+	"w/hRH8ZdGkVgz3e6uzcCyun0SHpmswSuMiitvA8gEsWC7l1bQ3TLTCePH/EnNpGCQej9ChwOvTlPIjP5",
+	"RBhIiiAGLxaklIBhyPmUFeCjoIbdG93Ijnd5hc50Q71AbR2O2hibZqVeoNmnrPTx+7klAqWJm+/czReH",
+	"pC+Z6arCPJNgeD4mxBFAtEEHhK01wOx+pkgOEp07mM7BdJJuFJ/vwWdbSOKCAEuI0D1Qs/s+Ejg0jHmF",
+	"s3slC3f6woZE0I/Q/RixndgOUOh2jCUKqrtIfjDz/MpgvkHyw5IfhSpl7HrHrZWQQa6/FmQMPu3aTIMF",
+	"R88JHSEhC2v0FFTZB2v1nX5VC+FQjQqW9WfljGmhYDnRxg4iRrVsmGrEcxXyyRW8CbmBGOuBG4HTKVeb",
+	"ArlVwBnPiiqHc/fOxgyqj3f2oumNhVZI6Ssl1Ym17LcE+ns6g4EwcGvDnUzYpfUzX6KqrzoXsSUIeo/V",
+	"dx6kga6JZXc3ZoUGaXMI5sYgDVLQTNKBAjggXn67BHhgEhVJmxhnDH9rg3VcxSkJ4ImDHQxStMwaBMdn",
+	"yNQFBwKFgjO+cWgisGrWv2jX2+hVjwcYwuhmb2+cU+sHdFNqG39V44ra318rs5z/N55K+yvmfB6+edFr",
+	"LZwAzwkwdJajiYNO3sBX0MHQ7aIPSxGYztP2WmsRvJmse2Brd1WS6cWJgbbdri1ZMBGr5q83nhv99Ntp",
+	"sky7L3g7+Qy1B+uCScOwkJBkB9/YcY4R6/UiJUhlmER7FqWpdQAjdZxhtvRZMiRHsbdtliNW8dUJPUwS",
+	"m19jna6Uc6GRNgnwvBSM69qJhOBarr2y3jFEQ9SzEBDNfU21Lm0NAuNjESl0Pf7wCgnT8ns8hWGcA+N9",
+	"y/3uM8G1FMWQvAhSb+3LlPz022kIzBXQatLxaKvkZEgcHlsAOW8m5IFT0MUghsQaE0gdWBDNSR0z6ivf",
+	"II44yXwqFKD3FosFGo+rMqzcxVNs7QDehn2/DiOxtqvWxweYc4HagqdCTFwMyXmWkT0T5+cxrs1AiTxI",
+	"9oa7w12btwCcliw5SB7jTzaLAFF9ZziHohhccDHnO3/ML9TQF6VMIEL6jtuaeLjFHJsh3sbEtDmNq9ky",
+	"kPBVxkNibQTr321Q12YZ+4tXU2qEyr9NAoLL4x2S06DsWeEAPwHuxcyCVTrhZp4TlKk0sxahfd0Cn1Y2",
+	"jO1LylgGj1SbBE3sEXgGFup18sjbPDlIfgSNORZLdaX7u7srSn+2K/nB+SNVPoiWT/e+t8r+bzAiP8OC",
+	"nECboyUHvxuORycK/YaGnX00z3fMqXTvHbtyP0WawGuT5m5TqZ3ixmFuI2pS6WEHPsbh6POHUWkJCvV7",
+	"JF7zyo6tdb5O177oKv6v0+VzNAnsqKjMp6Y8L7dGcl1RESuFbifGr65R/tQ3w3Ljho1rHPrmXMocX7et",
+	"iD4O7g5d8rhxhQvpvANIBE7Hjq1umHBrzU208w334UJ167agxfYb+HiPxNkk2Uco9BcOaL6hHm9e9Kc2",
+	"/PjJ7m7f5PVud4L6dByyt35Iq8wTBz1eP6gpEr++DrmFYRGWXaDoNROVIqYOvr6y1GTkpatr5XlTJ4Mq",
+	"P7LdIXGhCKcLYMDK1iflZALaZR2RzKf6oHgEmmNofAR11XfejnDFmDMmOyV1Oca/RL64s4tvJX1dt9VN",
+	"LSu4vkekC5O4ImjnChTMBTgNs5bJQnbgeztcfKhC3N9stxzELCFrxMITRR2euMf9Z+uPtVwsvpHwrNGu",
+	"nyJsLoLXpTWUtkqgF/mHxNOQ1f5qi6URtaOFV4FtSBKHB3aXNcQCWovQRG3Z3RNddCzHB6aNMMVwC9L4",
+	"OqjgdAoN4bZqnHJPCIgCTJE5EgwiR6UegBxEpftp4RjrX7r1WWZnTT8Eb+9QbmsW0eeVOlMouCwDAsaH",
+	"5L1R8y8pKwz9GOlQl0CqHmlgNnk/aL+UA3HdbTWzv/ukC5h3YmKKGc2+vhaVoL70GfRaDwZPx8bV5517",
+	"VgVv19Vi6XDMoDqErQ2FsGnTvep7jZswTp3dI7b7d/hmcKsad+A7OP/jGM6cemNGaVYUNokSFGn3kLo5",
+	"ajx5aIbWBRnhghSCTzCIyzwX8ihonNS2ciIWArNFkD3o5nIXjC1h9dOp4DAkjbEbc3218RMjbzfC0BA7",
+	"N2VAAzzllvBuqmEM2MIpMSJ4ozmXs0ceWKqvIrsjm4lCbBeF/Db09mR39yGx/7Bu8YVXktp/SCaqIkcn",
+	"1giszxEaRdcLyb6OYGf8FrT/+KFp3x3Yqb3U53A0KfhIqkJaSkUgmGZPNtvB9TKspb49w7MHP4PZGrqK",
+	"Xc69pkZJMSnj3J7EsJ7n7mxM2QG42b399bcUaReGQ59ucsGR7kmoCG6wbqeVUYcHWyXAWEE7WuiyCQev",
+	"cicucdgOf31lG5MY0+aeVLWlKpmNOFlMDBvjy7VRyZ1FEZhhyjzLqMw9bT2oavegBsmyxTGnDS1UKsK7",
+	"viwSNvfoWns5U/wOaSTt8w5giyln//tU1CE5bToZWjphyoYugI+FzDBvKbPaTTPMvGQy1RmKEhc7MbfS",
+	"r9kgvsbyCFOihAukEKVFAc0QFzocmQICpq0mFahYQvoOAs7HEdOebNrtPRJ3N7P4gTWVpeTiCMKdYCAr",
+	"9anAmDBsrsPnCmtBVEb5t841lhCv5iBfB7fw/O3uOcaSVN1x7bdW+FY2Fa0vLY/4wkTr7h36YMLSmr7L",
+	"czf23OFYS2RTCZbdqqnhblOQ8N8gvCOtYEvgWK+gPLP6+miStNoBN01ijeS9D4oNU19vTapoER0ujprM",
+	"2fug13jHqJtqxH4e3479Lwm2GbXcKRbquovaBG6FgiZz43DhQin3GbAJu3aubCX+SDWAU2kr8ST1LbaI",
+	"4KB8HnH+OTzkNgXBgq1f/7e9ofry2IYE0yvZys5yJhNwOByeJYRxYus/MV/4p99Og3w3zHaxydUa4+ut",
+	"Si6Pw1IUQDiVUsybTmC4lUfKpQ9u5ya153P4c1/cK9q9bSPutXfn6Oub5PZgMMLyOdpNVqTWlt1nVTU2",
+	"RvA7YlMBZbT41c4n93WI67vxItmAY4h+a905LtRetx7/gm/iYWM074XtfGtjr0ISZiWaV7dqmK1iglvF",
+	"Svx3RK4/OiSxNtCgrkfqyUEyj1teDQwmUdsO1kzhYEQKxi+GZBt0UsDzX4Mpuji1391QOMDlrvhPFDwY",
+	"qjy4O7z1VYNQLa+r3qKqi9eiTNeIidDrbtl3Y8FOCO12zO52T5e+bFV/Lmk+BfTKC4n2gPnd73YEJuyI",
+	"/SQor/1Y7eRxn12PrQLwK07k7ZGNJoJsnz7FYhi4BKsOikpnYhZNI253zrsneRlvz7eRvNyPt8Et9c15",
+	"5c3EyX1msdQoiGi0KrNLO6ZSI17d6iso/dAWAUG7Bt/kBSZEhFlHrdxkm1VttVjM7+ILx5dMRpuorJ+3",
+	"ACoht+htkc2gO6Lsj69PydIhTIkU+ffp4Tv8RoXBQMVyaKKF1tVrjqlQBYnhZqsVx72lzyi4GWauskPt",
+	"PT58XPd0gzhtbSTW30SJfQolDKN8oVTjiiYGdYn3WtOz1b4tbni2u5bcq/0ZaZASuVJbFbRUzv45zMu1",
+	"lmUPmMlJe++KTOklEC4aJhbUhxViQhh/7rLzA7PbF2/OLMs7+uXklOxgadfOJ5Zf7/Sn38U6Od+rYRhv",
+	"Gv3A5uGqxJWlG/GlEN+6GdiyAFuFkJuUFyylgtrO60YW057U3iF5bbqdt4fNhbxQGEt9jgmy2JqHe/mr",
+	"0E6fT0UBLtc0KhZxwvt0cHSTSr+cTOr3K0D+FWVTt9GiRwovJzRsJhiRK66Xhk3Be1wUfnCNd+6/ci5W",
+	"b+X6lmyIr65jQnyqGePntpfF9rV0M3q10dglJytVMGBcAVcM2wKqamSrwpw25s3ynmIz/2zF51hvsqRr",
+	"HxFb0T3aYsETIV0705TYL3z5HJBHg0fIHs37LponpP3sbGxpJWT706++1xzWGQ7CxheDpVY3A/8fe0WD",
+	"eFfe63QtDSgtpOmCUqj6q7XdniE9++/2DulAse4RdO8p4ptUBNrDfLsxspfh918bLme/gYkNQ4wEtqGH",
+	"8CdJDCqqmjjNLwZkBqmNFdztEHLWVpHxhU005KAD9bBHX/3gmt5sl+vd/vj3dinf26q5YZe7L0i5Nc9q",
+	"jbaVkh359vi6DO3I5+3DfO0v0kf/mRyvPle6m5D8d5uAbNilUW5ZNv2HLa5a+rA7qju2aswnE+ZsjP10",
+	"Nal7A6Pe3f89eqZceQrjZFywyVSjCn03X7K9vQ3iWUStp6H1ulHsKWxUPyQnjXhStSvPxPmMJW3y951Q",
+	"81+CYujFw8815jGr4hVOdTOuM+6tf4o4yZA+Q+H6VcS8Vo+oP/Z9y9z6/R+2G1p/GzsuhW6u//8IOo4K",
+	"W+hRY4a1wh096i7UqPTLKsyzffri3NEcOd2kUdlnrNf7RogvroptxcvMbWIAuLfOL6rEkSMqNaNFsXDV",
+	"YK59Xh0NrIrm23xYFViwC/PS0YfT3mK/O2DGX1+5X7vR6BdU7Wee/VXqd0elfvcqgL88DbinJC/QiL/C",
+	"qry711fKalOjmRy7D09AuwN3owiLGdPYvrCOMl0AlKqVpWNqsCPMt2ni/kDcd9tq6K0t76+SQ34TxsB/",
+	"Iy/6MthJ28JuF0ncWCW8jVuvVfLwV13F11hXkYWeZS9r3I2TsvWVsu1ZxT35lnacVX7/iO8WGpIPHDVH",
+	"a/sTa9A3fRadUdp4o2KJX2YiJ4E/nyzzLrS/srKXIcOFJvaDekGumL3OzyD2Pjh84sJjW+78xOZbqb6z",
+	"z5xMqU2waIvITWho0+KmpdQnE0ZX/sNvQeqlybDEXXW/VNIfi/8CKqK2LYD69vxKNy4t2JyZtnDINVxs",
+	"lwkQG4BTrYo+m5G0ArF6U+Nq5PqrXOpbKJd6eMWjlVjX4ZpbllitSdK1BVZtlP2rxOpWktO3bW2k+lKO",
+	"+Lqiq1szxXTLyqwAxypuihQeyqojLwug0hZu2dKI5jvNEkrbUbz1rbMuBn/AHceV2754qT3ltytWQ7Vr",
+	"uebOaVyxbsKv/SfP2lcf79/eCLhG4qzKeNsomv26VXTWVJp9ltKPOpcU7VBjty4nlaatdFLMfrKduUWR",
+	"+1NskGTafqH9yZrfP143I/xnOROfc1b/MIPwrzp7t/4Fl2r9nTP8RtD/DwACthIw8aIAAA==",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartIdempotencyCleanup deletes expired idempotency records every interval
+// until ctx is cancelled.
+func StartIdempotencyCleanup(ctx context.Context, repo repository.IdempotencyRepoInterface, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			n, err := repo.DeleteExpired(ctx)
+			if err != nil {
+				logger.L(ctx).Error("Idempotency cleanup failed", zap.Error(err))
+				continue
+			}
+			if n > 0 {
+				logger.L(ctx).Info("Deleted expired idempotency records", zap.Int64("count", n))
+			}
+		}
+	}()
+}
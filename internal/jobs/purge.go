@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartUserPurge permanently removes users soft-deleted longer than retention,
+// once at startup and then every interval, until ctx is cancelled.
+func StartUserPurge(ctx context.Context, svc service.UserServiceInterFace, retention, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purgeOnce(ctx, svc, retention)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func purgeOnce(ctx context.Context, svc service.UserServiceInterFace, retention time.Duration) {
+	log := logger.L(ctx)
+	n, err := svc.PurgeDeleted(ctx, retention)
+	if err != nil {
+		log.Error("User purge failed", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		log.Info("Purged soft-deleted users", zap.Int("count", n), zap.Duration("retention", retention))
+	}
+}
@@ -2,8 +2,14 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/problem"
+	"go-crud-oapi/pkg/requestctx"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,50 +18,151 @@ import (
 
 type contextKey string
 
-const UserRoleKey contextKey = "userRole"
+const (
+	UserIDKey      contextKey = "userID"
+	UserRoleKey    contextKey = "userRole"
+	MFAKey         contextKey = "mfa"
+	TokenIDKey     contextKey = "tokenID"
+	TokenExpiryKey contextKey = "tokenExpiry"
+	APITokenKey    contextKey = "apiToken" // id of the API token used, absent for JWTs
+	ScopesKey      contextKey = "scopes"   // []auth.Permission granted to the API token
+)
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET")) // fallback if not from .env
+// RevocationChecker reports whether an access token jti has been revoked.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
 
-func JWTAuthMiddleware(next http.Handler) http.Handler {
+// APITokenAuthenticator resolves a personal access token to its owner. It
+// returns service.ErrInvalidAPIToken for unknown, revoked or expired tokens.
+type APITokenAuthenticator interface {
+	AuthenticateAPIToken(ctx context.Context, token string) (*model.User, *model.APIToken, error)
+}
+
+// JWTAuth authenticates requests carrying either an access JWT or, when the
+// bearer token starts with auth.APITokenPrefix, a personal access token.
+type JWTAuth struct {
+	Keys        *auth.KeyManager
+	Revocations RevocationChecker
+	APITokens   APITokenAuthenticator
+}
+
+func NewJWTAuth(keys *auth.KeyManager, revocations RevocationChecker, apiTokens APITokenAuthenticator) *JWTAuth {
+	return &JWTAuth{Keys: keys, Revocations: revocations, APITokens: apiTokens}
+}
+
+func (a *JWTAuth) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if !strings.HasPrefix(authHeader, "Bearer") {
-			http.Error(w, "Unauthorized: missing token", http.StatusUnauthorized)
+			problem.Write(w, r, problem.Unauthorized("missing token"))
 			return
 		}
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return jwtSecret, nil
-		})
+		if strings.HasPrefix(tokenStr, auth.APITokenPrefix) {
+			a.serveAPIToken(w, r, next, tokenStr)
+			return
+		}
+
+		token, err := a.Keys.ParseAccessToken(tokenStr)
 
 		if err != nil || !token.Valid {
-			http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+			problem.Write(w, r, problem.Unauthorized("invalid token"))
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			http.Error(w, "Unauthorized: claims error", http.StatusUnauthorized)
+			problem.Write(w, r, problem.Unauthorized("claims error"))
 			return
 		}
 
 		// Check expiry
-		if exp, ok := claims["exp"].(float64); ok && time.Now().Unix() > int64(exp) {
-			http.Error(w, "Unauthorized: token expired", http.StatusUnauthorized)
+		exp, ok := claims["exp"].(float64)
+		if !ok || time.Now().Unix() > int64(exp) {
+			problem.Write(w, r, problem.Unauthorized("token expired"))
+			return
+		}
+
+		if use, _ := claims["token_use"].(string); use != auth.TokenUseAccess {
+			problem.Write(w, r, problem.Unauthorized("not an access token"))
+			return
+		}
+
+		jti, ok := claims["jti"].(string)
+		if !ok {
+			problem.Write(w, r, problem.Unauthorized("token id missing"))
+			return
+		}
+
+		revoked, err := a.Revocations.IsRevoked(r.Context(), jti)
+		if err != nil {
+			problem.Write(w, r, problem.Status(http.StatusInternalServerError, "the token could not be verified"))
+			return
+		}
+		if revoked {
+			problem.Write(w, r, problem.Unauthorized("token revoked"))
 			return
 		}
 
 		role, ok := claims["role"].(string)
 		if !ok {
-			http.Error(w, "Unauthorized: role missing", http.StatusUnauthorized)
+			problem.Write(w, r, problem.Unauthorized("role missing"))
+			return
+		}
+
+		sub, _ := claims["sub"].(string)
+		userID, err := strconv.ParseUint(sub, 10, 0)
+		if err != nil {
+			problem.Write(w, r, problem.Unauthorized("subject missing"))
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), UserRoleKey, role)
+		ctx := context.WithValue(r.Context(), UserIDKey, uint(userID))
+		ctx = context.WithValue(ctx, UserRoleKey, role)
+		ctx = context.WithValue(ctx, MFAKey, hasAMR(claims, "otp"))
+		ctx = requestctx.WithActor(ctx, requestctx.Caller{UserID: uint(userID)})
+		ctx = context.WithValue(ctx, TokenIDKey, jti)
+		ctx = context.WithValue(ctx, TokenExpiryKey, time.Unix(int64(exp), 0))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// serveAPIToken authenticates a personal access token. The caller gets the
+// owner's current role, narrowed to the token's scopes by RequirePermission.
+func (a *JWTAuth) serveAPIToken(w http.ResponseWriter, r *http.Request, next http.Handler, tokenStr string) {
+	user, token, err := a.APITokens.AuthenticateAPIToken(r.Context(), tokenStr)
+	if errors.Is(err, service.ErrInvalidAPIToken) {
+		problem.Write(w, r, problem.Unauthorized("invalid token"))
+		return
+	}
+	if err != nil {
+		problem.Write(w, r, problem.Status(http.StatusInternalServerError, "the token could not be verified"))
+		return
+	}
+
+	scopes := make([]auth.Permission, 0)
+	for _, s := range strings.Split(token.Scopes, ",") {
+		scopes = append(scopes, auth.Permission(s))
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, user.ID)
+	ctx = context.WithValue(ctx, UserRoleKey, user.Role)
+	ctx = context.WithValue(ctx, MFAKey, token.MFA)
+	ctx = requestctx.WithActor(ctx, requestctx.Caller{UserID: user.ID, APITokenID: token.ID})
+	ctx = context.WithValue(ctx, APITokenKey, token.ID)
+	ctx = context.WithValue(ctx, ScopesKey, scopes)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// hasAMR reports whether the token's amr claim lists method.
+func hasAMR(claims jwt.MapClaims, method string) bool {
+	amr, _ := claims["amr"].([]any)
+	for _, m := range amr {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -1,20 +1,16 @@
 package middleware
 
 import (
-	"context"
+	"go-crud-oapi/pkg/requestctx"
 	"net/http"
 
 	"github.com/google/uuid"
 )
 
-type ctxKey string
-
-const requestIDKey ctxKey = "requestID"
-
 func RequestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := uuid.New().String()
-		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		ctx := requestctx.WithRequestID(r.Context(), id)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"go-crud-oapi/pkg/requestctx"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyStore persists the outcome of requests made with an Idempotency-Key.
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, record *model.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key and body, and rejects reuse of a key with a different
+// body. Requests without the header pass straight through.
+//
+// A key is reserved for Lease while its request runs and, once it has an
+// answer, kept for TTL. The lease frees keys whose request never finished,
+// for example because the process died.
+type Idempotency struct {
+	Store IdempotencyStore
+	TTL   time.Duration
+	Lease time.Duration
+}
+
+func NewIdempotency(store IdempotencyStore, ttl, lease time.Duration) *Idempotency {
+	return &Idempotency{Store: store, TTL: ttl, Lease: lease}
+}
+
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		log := logger.L(r.Context()).With(zap.String("idempotency_key", key))
+
+		if len(key) > maxIdempotencyKeyLength {
+			log.Warn("Idempotency key too long")
+			problem.Write(w, r, problem.InvalidRequest(fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)))
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Warn("Failed to read request body", zap.Error(err))
+			problem.Write(w, r, problem.InvalidRequest("the request body could not be read"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the caller so clients cannot collide with each other.
+		record := &model.IdempotencyRecord{
+			Key:         strconv.FormatUint(uint64(requestctx.Actor(r.Context()).UserID), 10) + ":" + key,
+			Fingerprint: fingerprint(r, body),
+			ExpiresAt:   time.Now().Add(i.Lease),
+		}
+
+		stored, reserved, err := i.Store.Reserve(r.Context(), record)
+		if err != nil {
+			log.Error("Failed to reserve idempotency key", zap.Error(err))
+			problem.Write(w, r, problem.Status(http.StatusInternalServerError, ""))
+			return
+		}
+
+		if !reserved {
+			switch {
+			case stored.Fingerprint != record.Fingerprint:
+				log.Warn("Idempotency key reused with a different request")
+				problem.Write(w, r, problem.New(problem.TypeConflict, http.StatusConflict,
+					"this "+IdempotencyKeyHeader+" was already used for a different request").With("resource", "idempotency key"))
+			case stored.StatusCode == 0:
+				log.Warn("Request with this idempotency key is still in progress")
+				w.Header().Set("Retry-After", "1")
+				problem.Write(w, r, problem.New(problem.TypeConflict, http.StatusConflict,
+					"a request with this "+IdempotencyKeyHeader+" is still in progress").With("resource", "idempotency key"))
+			default:
+				log.Info("Replaying stored response", zap.Int("status", stored.StatusCode))
+				replay(w, stored)
+			}
+			return
+		}
+
+		// Server errors and panics are not final: forget the key so a retry
+		// runs again. A panic is passed on once the key is released.
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			v := recover()
+			if err := i.Store.Release(r.Context(), record.Key); err != nil {
+				log.Error("Failed to release idempotency key", zap.Error(err))
+			}
+			if v != nil {
+				panic(v)
+			}
+		}()
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.status >= http.StatusInternalServerError {
+			return
+		}
+
+		completed = true
+		headers, _ := json.Marshal(rec.Header())
+		record.StatusCode = rec.status
+		record.Headers = string(headers)
+		record.Body = rec.body.Bytes()
+		record.ExpiresAt = time.Now().Add(i.TTL)
+		if err := i.Store.Complete(r.Context(), record); err != nil {
+			// Better to run a retry again than answer it 409 until the key expires.
+			log.Error("Failed to store idempotent response", zap.Error(err))
+			if err := i.Store.Release(r.Context(), record.Key); err != nil {
+				log.Error("Failed to release idempotency key", zap.Error(err))
+			}
+		}
+	})
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, stored *model.IdempotencyRecord) {
+	var headers http.Header
+	if err := json.Unmarshal([]byte(stored.Headers), &headers); err == nil {
+		for k, v := range headers {
+			w.Header()[k] = v
+		}
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	w.Write(stored.Body)
+}
+
+// responseRecorder passes the response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/logger"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// maxTrackedIPs bounds memory use. Once it is reached, stale entries are
+// swept, and if that is not enough the IP that failed longest ago is dropped.
+const maxTrackedIPs = 10000
+
+type ipFailures struct {
+	count        int
+	inFlight     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+// LoginThrottle counts failed logins per client IP and answers 429 with
+// Retry-After while an IP is backing off. A 401 from the wrapped handler is a
+// failure. Attempts still in progress count as failures when admitting new
+// ones, so parallel requests cannot all slip in before the first is recorded.
+// Successful logins do not clear the count, so an attacker cannot reset it
+// with an account of their own; it is forgotten once the IP has not failed
+// for Backoff.Max. State is kept in memory, so every instance throttles
+// independently.
+type LoginThrottle struct {
+	Backoff auth.Backoff
+
+	mu  sync.Mutex
+	ips map[string]*ipFailures
+}
+
+func NewLoginThrottle(backoff auth.Backoff) *LoginThrottle {
+	return &LoginThrottle{Backoff: backoff, ips: make(map[string]*ipFailures)}
+}
+
+func (t *LoginThrottle) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r)
+
+		if wait := t.acquire(ip); wait > 0 {
+			logger.L(r.Context()).Warn("Login throttled", zap.String("ip", ip), zap.Duration("retry_after", wait))
+			tooManyRequests(w, r, wait, "too many failed login attempts")
+			return
+		}
+
+		ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			t.release(r, ip, ww.Status() == http.StatusUnauthorized)
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// acquire admits an attempt from ip, or returns how long to wait if the IP is
+// blocked or would be once the attempts already in progress fail. An admitted
+// attempt must be released.
+func (t *LoginThrottle) acquire(ip string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	f, ok := t.ips[ip]
+	if ok && f.inFlight == 0 && stale(f, now, t.Backoff.Max) {
+		f.count = 0
+	}
+	if !ok {
+		if len(t.ips) >= maxTrackedIPs {
+			t.evict(now)
+		}
+		f = &ipFailures{}
+		t.ips[ip] = f
+	}
+
+	if wait := f.blockedUntil.Sub(now); wait > 0 {
+		return wait
+	}
+	if wait := t.Backoff.LockFor(f.count + f.inFlight); f.inFlight > 0 && wait > 0 {
+		return wait
+	}
+	f.inFlight++
+	return 0
+}
+
+// release ends an attempt admitted by acquire, counting it if it failed.
+func (t *LoginThrottle) release(r *http.Request, ip string, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f := t.ips[ip]
+	f.inFlight--
+	if !failed {
+		if f.count == 0 && f.inFlight == 0 {
+			delete(t.ips, ip)
+		}
+		return
+	}
+
+	now := time.Now()
+	f.count++
+	f.lastFailure = now
+
+	if d := t.Backoff.LockFor(f.count); d > 0 {
+		f.blockedUntil = now.Add(d)
+		logger.L(r.Context()).Warn("IP blocked after failed logins",
+			zap.String("ip", ip), zap.Int("failures", f.count), zap.Time("blocked_until", f.blockedUntil))
+	}
+}
+
+// evict makes room for a new IP: it forgets IPs that are not blocked and have
+// not failed for the longest backoff period, and if none are, the idle IP that
+// failed longest ago. The caller must hold t.mu.
+func (t *LoginThrottle) evict(now time.Time) {
+	var oldest string
+	for ip, f := range t.ips {
+		if f.inFlight > 0 {
+			continue
+		}
+		if stale(f, now, t.Backoff.Max) {
+			delete(t.ips, ip)
+			continue
+		}
+		if oldest == "" || f.lastFailure.Before(t.ips[oldest].lastFailure) {
+			oldest = ip
+		}
+	}
+	if len(t.ips) >= maxTrackedIPs && oldest != "" {
+		delete(t.ips, oldest)
+	}
+}
+
+// stale reports whether f is no longer blocked and has not failed for idle.
+func stale(f *ipFailures, now time.Time, idle time.Duration) bool {
+	return now.After(f.blockedUntil) && now.Sub(f.lastFailure) > idle
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"go-crud-oapi/pkg/validation"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"go.uber.org/zap"
+)
+
+func init() {
+	// PATCH /users/{id} and PATCH /me accept JSON merge patches, which
+	// openapi3filter has no decoder for.
+	openapi3filter.RegisterBodyDecoder("application/merge-patch+json", openapi3filter.JSONBodyDecoder)
+}
+
+// OpenAPIValidator rejects requests whose parameters or body do not match
+// api/openapi.yaml with a 400 listing each violation. With ValidateResponses
+// set it also checks what the handlers send back and logs any mismatch;
+// responses are never altered. Requests for paths outside the spec pass
+// straight through.
+type OpenAPIValidator struct {
+	Router            routers.Router
+	ValidateResponses bool
+}
+
+func NewOpenAPIValidator(spec *openapi3.T, validateResponses bool) (*OpenAPIValidator, error) {
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAPIValidator{Router: router, ValidateResponses: validateResponses}, nil
+}
+
+func (v *OpenAPIValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.Router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		log := logger.L(r.Context()).With(zap.String("operation", route.Operation.OperationID))
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:          true,
+				SkipSettingDefaults: true,
+				// Credentials are checked by JWTAuth on the routes that need them.
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			log.Warn("Request does not match the API spec", zap.Error(err))
+			problem.Write(w, r, problem.InvalidRequest("the request does not match the API spec").With("errors", specFieldErrors("", err)))
+			return
+		}
+
+		if !v.ValidateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		output := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.status,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+			Options: &openapi3filter.Options{
+				MultiError:            true,
+				IncludeResponseStatus: true,
+			},
+		}
+		if err := openapi3filter.ValidateResponse(r.Context(), output); err != nil {
+			log.Error("Response does not match the API spec", zap.Int("status", rec.status), zap.Error(err))
+		}
+	})
+}
+
+// specFieldErrors flattens the errors returned by openapi3filter into one
+// entry per violation. Parameters are reported by name and body fields by
+// their dotted path, so that they read like the 422 errors of the handlers.
+func specFieldErrors(field string, err error) []validation.FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var fields []validation.FieldError
+		for _, err := range e {
+			fields = append(fields, specFieldErrors(field, err)...)
+		}
+		return fields
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			field = e.Parameter.Name
+		case e.RequestBody != nil:
+			field = "body"
+		}
+		if e.Err == nil {
+			return []validation.FieldError{{Field: field, Rule: "spec", Message: e.Reason}}
+		}
+		return specFieldErrors(field, e.Err)
+	case *openapi3.SchemaError:
+		if path := e.JSONPointer(); len(path) > 0 {
+			if field == "body" {
+				field = strings.Join(path, ".")
+			} else {
+				field += "." + strings.Join(path, ".")
+			}
+		}
+		return []validation.FieldError{{Field: field, Rule: e.SchemaField, Message: e.Reason}}
+	default:
+		return []validation.FieldError{{Field: field, Rule: "spec", Message: err.Error()}}
+	}
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/problem"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxTrackedKeys bounds the memory used by each RequestLimit. Once it is
+// reached, expired windows are swept, and if that is not enough the oldest
+// window is dropped.
+const maxTrackedKeys = 10000
+
+type requestWindow struct {
+	start time.Time
+	count int
+}
+
+// RequestLimit allows at most Limit requests per key in each Window and
+// answers 429 with Retry-After beyond that. Unlike LoginThrottle it counts
+// every request, whatever the response, for endpoints such as the password
+// reset email whose answer never says whether it did anything. A Limit of
+// zero or less disables it. State is kept in memory, so every instance
+// limits independently.
+type RequestLimit struct {
+	Limit  int
+	Window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]*requestWindow
+}
+
+func NewRequestLimit(limit int, window time.Duration) *RequestLimit {
+	return &RequestLimit{Limit: limit, Window: window, windows: make(map[string]*requestWindow)}
+}
+
+// Middleware limits requests by the key that key extracts. Requests for which
+// it returns "" are not limited.
+func (l *RequestLimit) Middleware(name string, key func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+			if k == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if wait := l.take(k); wait > 0 {
+				logger.L(r.Context()).Warn("Request limit reached", zap.String("limit", name), zap.Duration("retry_after", wait))
+				tooManyRequests(w, r, wait, "too many requests, try again later")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// take counts a request for key, returning how long to wait if it is over
+// the limit.
+func (l *RequestLimit) take(key string) time.Duration {
+	if l.Limit <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	win, ok := l.windows[key]
+	if !ok || now.Sub(win.start) >= l.Window {
+		if !ok && len(l.windows) >= maxTrackedKeys {
+			l.evict(now)
+		}
+		win = &requestWindow{start: now}
+		l.windows[key] = win
+	}
+	if win.count >= l.Limit {
+		return win.start.Add(l.Window).Sub(now)
+	}
+	win.count++
+	return 0
+}
+
+// evict drops expired windows, and the oldest one if none had expired. The
+// caller must hold l.mu.
+func (l *RequestLimit) evict(now time.Time) {
+	var oldest string
+	for key, win := range l.windows {
+		if now.Sub(win.start) >= l.Window {
+			delete(l.windows, key)
+			continue
+		}
+		if oldest == "" || win.start.Before(l.windows[oldest].start) {
+			oldest = key
+		}
+	}
+	if len(l.windows) >= maxTrackedKeys && oldest != "" {
+		delete(l.windows, oldest)
+	}
+}
+
+// ClientIP keys a RequestLimit by the client's IP address.
+func ClientIP(r *http.Request) string {
+	return clientIP(r)
+}
+
+// BodyEmail keys a RequestLimit by the "email" member of a JSON request body,
+// ignoring case. The body is left for the handler to read.
+func BodyEmail(r *http.Request) string {
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var req struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(req.Email))
+}
+
+// tooManyRequests answers 429, telling the client how long to wait.
+func tooManyRequests(w http.ResponseWriter, r *http.Request, wait time.Duration, detail string) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	problem.Write(w, r, problem.Status(http.StatusTooManyRequests, detail).With("retry_after", seconds))
+}
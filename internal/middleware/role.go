@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/problem"
+	"net/http"
+)
+
+// RequirePermission allows the request through only if the caller is Allowed
+// every one of perms. It must run after JWTAuth.Middleware.
+func RequirePermission(perms ...auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, perm := range perms {
+				if !Allowed(r.Context(), perm) {
+					problem.Write(w, r, problem.Forbidden("missing permission "+string(perm)).With("permission", perm))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Allowed reports whether the caller JWTAuth stored in ctx holds perm. Their
+// role must grant it and, if they used an API token, so must its scopes.
+func Allowed(ctx context.Context, perm auth.Permission) bool {
+	role, _ := ctx.Value(UserRoleKey).(string)
+	if !auth.HasPermission(role, perm) {
+		return false
+	}
+	scopes, scoped := ctx.Value(ScopesKey).([]auth.Permission)
+	if !scoped {
+		return true
+	}
+	for _, s := range scopes {
+		if s == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireSession rejects callers that authenticated with an API token, for
+// routes that manage the account itself. It must run after JWTAuth.Middleware.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(APITokenKey).(uint); ok {
+			problem.Write(w, r, problem.Forbidden("not allowed with an API token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireMFA rejects callers whose role is one of roles unless their token was
+// issued after a second factor. It must run after JWTAuth.Middleware.
+func RequireMFA(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value(UserRoleKey).(string)
+			mfa, _ := r.Context().Value(MFAKey).(bool)
+			for _, required := range roles {
+				if role == required && !mfa {
+					problem.Write(w, r, problem.Forbidden("two-factor authentication required"))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
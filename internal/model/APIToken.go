@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// APIToken is a long-lived personal access token. Only its hash is stored;
+// Prefix is kept so owners can tell their tokens apart. Scopes is a comma
+// separated list of permissions, further limited by the owner's role.
+type APIToken struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"index;not null"`
+	Name       string `gorm:"not null"`
+	Prefix     string `gorm:"not null"`
+	TokenHash  string `gorm:"uniqueIndex;not null"`
+	Scopes     string `gorm:"not null"`
+	MFA        bool   `gorm:"column:mfa;not null;default:false"` // created from a session that passed a second factor
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
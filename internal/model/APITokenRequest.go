@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// CreateAPITokenRequest is the body of POST /me/tokens and POST /users/{id}/tokens.
+// A nil ExpiresAt creates a token that is valid until revoked.
+type CreateAPITokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateServiceAccountRequest is the body of POST /service-accounts.
+type CreateServiceAccountRequest struct {
+	Name string `json:"name" validate:"required,min=3,max=50"`
+	Role string `json:"role" validate:"required,oneof=admin user viewer"`
+}
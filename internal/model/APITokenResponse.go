@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// APITokenResponse describes a token without its secret.
+type APITokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APITokenCreatedResponse is returned once, when a token is created. The
+// token itself cannot be retrieved again.
+type APITokenCreatedResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
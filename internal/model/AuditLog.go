@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+const (
+	AuditActionCreate         = "user.create"
+	AuditActionUpdate         = "user.update"
+	AuditActionDelete         = "user.delete"
+	AuditActionPasswordChange = "user.password_change"
+	AuditActionRestore        = "user.restore"
+	AuditActionPurge          = "user.purge"
+	AuditActionUnlock         = "user.unlock"
+	AuditActionMFAEnable      = "user.mfa_enable"
+	AuditActionMFADisable     = "user.mfa_disable"
+	AuditActionPasswordReset  = "user.password_reset"
+	AuditActionEmailVerify    = "user.email_verify"
+	AuditActionTokenCreate    = "user.token_create"
+	AuditActionTokenRevoke    = "user.token_revoke"
+)
+
+// Audit actors. Entries recorded before actor IDs were stored hold the
+// caller's email instead.
+const (
+	AuditActorUser   = "user"
+	AuditActorSystem = "system" // background jobs and unauthenticated flows such as password reset
+)
+
+// AuditLog is an append-only record of a single user mutation.
+type AuditLog struct {
+	ID           uint   `gorm:"primaryKey"`
+	Actor        string `gorm:"index;not null"`
+	ActorUserID  *uint  `gorm:"index"` // the signed-in user, nil for the system
+	ActorTokenID *uint  // the API token they used, if any
+	Action       string `gorm:"index;not null"`
+	TargetUserID uint   `gorm:"index;not null"`
+	Changes      string // JSON object of field -> {"old": ..., "new": ...}
+	RequestID    string
+	CreatedAt    time.Time `gorm:"index"`
+}
+
+// FieldChange is one entry of AuditLog.Changes.
+type FieldChange struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+type AuditListParams struct {
+	Limit        int
+	ActorUserID  *uint
+	Action       string
+	TargetUserID *uint
+	From         *time.Time
+	To           *time.Time
+	BeforeID     uint // keyset cursor: only entries with a smaller id
+}
+
+type AuditPage struct {
+	Items      []AuditLog
+	NextCursor string
+}
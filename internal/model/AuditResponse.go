@@ -0,0 +1,23 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLogResponse struct {
+	ID           uint            `json:"id"`
+	Actor        string          `json:"actor"`
+	ActorUserID  *uint           `json:"actor_user_id,omitempty"`
+	ActorTokenID *uint           `json:"actor_token_id,omitempty"`
+	Action       string          `json:"action"`
+	TargetUserID uint            `json:"target_user_id"`
+	Changes      json.RawMessage `json:"changes,omitempty"`
+	RequestID    string          `json:"request_id,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type AuditListResponse struct {
+	Items      []AuditLogResponse `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
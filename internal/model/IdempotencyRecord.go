@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// IdempotencyRecord stores the outcome of a request made with an
+// Idempotency-Key so a retry can be answered with the original response.
+// StatusCode is 0 while the first request is still being processed, and
+// ExpiresAt is then the end of its lease.
+type IdempotencyRecord struct {
+	Key         string `gorm:"column:idempotency_key;primaryKey"`
+	Fingerprint string `gorm:"not null"`
+	StatusCode  int
+	Headers     string // JSON encoded http.Header
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index;not null"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// TOTPEnrollRequest is the body of POST /me/mfa/totp. The current password is
+// asked for again before a new authenticator is bound to the account.
+type TOTPEnrollRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// MFACodeRequest carries a TOTP code, used to confirm enrollment or disable it.
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// MFALoginRequest is the body of POST /login/mfa, the second login step.
+// Exactly one of Code and RecoveryCode is expected.
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// TOTPEnrollmentResponse is returned once when TOTP enrollment starts.
+// QRCode is a data: URI of a PNG encoding OTPAuthURI.
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"`
+}
+
+// RecoveryCodesResponse holds freshly generated recovery codes. They are only
+// ever shown here.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAChallengeResponse is returned by POST /login instead of tokens when the
+// account has two-factor authentication enabled.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// RefreshToken is a persisted, single-use refresh token. Tokens issued from
+// the same login share a FamilyID so the whole chain can be revoked at once.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index;not null"`
+	FamilyID  string    `gorm:"index;not null"`
+	TokenHash string    `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// RevokedToken records the jti of an access token that must no longer be
+// accepted, kept until the token would have expired anyway.
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
@@ -0,0 +1,29 @@
+package model
+
+// UserListParams describes a single page request against the users table.
+type UserListParams struct {
+	Limit    int
+	Role     string
+	MinAge   *int
+	MaxAge   *int
+	Email    string // case-insensitive substring
+	Name     string // case-insensitive substring
+	SortBy   string // id, name, email or age
+	SortDesc bool
+	After    *UserCursor
+
+	IncludeDeleted bool
+}
+
+// UserCursor marks the last row of the previous page. It is handed to clients
+// as an opaque, base64 encoded string.
+type UserCursor struct {
+	Sort  string `json:"s"`
+	Value any    `json:"v,omitempty"`
+	ID    uint   `json:"id"`
+}
+
+type UserPage struct {
+	Items      []User `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,28 @@
+package model
+
+// CreateUserRequest is the body accepted by POST /users.
+type CreateUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Age      int    `json:"age"`
+	Role     string `json:"role"`
+	Password string `json:"password"`
+}
+
+// UpdateMeRequest is the document PATCH /me is applied to. It only holds the
+// fields users may change on their own profile.
+type UpdateMeRequest struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+// UpdateUserRequest is the body accepted by PUT /users/{id}.
+type UpdateUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Age      int    `json:"age"`
+	Role     string `json:"role"`
+	Password string `json:"password,omitempty"`
+}
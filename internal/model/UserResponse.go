@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// UserSummaryResponse is the only view of other users given to viewers.
+type UserSummaryResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserResponse is the view of other users given to regular users.
+// DeletedAt is only set when an admin asked for deleted users.
+type UserResponse struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// AdminUserResponse is the full view of a user, returned to administrators and
+// to users reading their own record. It deliberately has no password field.
+type AdminUserResponse struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+	Age   int    `json:"age"`
+	Role  string `json:"role"`
+
+	EmailVerified  bool `json:"email_verified"`
+	ServiceAccount bool `json:"service_account"`
+
+	Version     uint       `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// UserListResponse holds one page of users. Each item is whichever of
+// UserSummaryResponse, UserResponse or AdminUserResponse the caller may see.
+type UserListResponse struct {
+	Items      []any  `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
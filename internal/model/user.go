@@ -1,12 +1,31 @@
 package model
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type User struct {
 	ID       uint   `gorm:"primaryKey" json:"id"`
 	Name     string `json:"name" validate:"required,min=3,max=50"`
-	Email    string `json:"email" gorm:"uniqueIndex"`
+	Email    string `json:"email" validate:"required,email" gorm:"uniqueIndex"`
 	Phone    string `json:"phone" validate:"required,e164" gorm:"uniqueIndex"`
 	Age      int    `json:"age" validate:"gte=0,lte=130"`
 	Role     string `json:"role" validate:"required,oneof=admin user viewer"`
-	Password string `json:"password,omitempty"` // never return in response
+	Password string `json:"-" validate:"required,password"` // never serialized; use the request/response types in UserRequest.go and UserResponse.go
+
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`              // soft delete; GORM hides these rows unless Unscoped
+	Version   uint           `json:"-" gorm:"not null;default:1"` // bumped on every profile change; exposed as the ETag
+
+	FailedLogins int        `json:"-" gorm:"not null;default:0"` // consecutive failed logins, reset on success or unlock
+	LockedUntil  *time.Time `json:"-"`                           // logins are refused until then
+
+	EmailVerifiedAt *time.Time `json:"-"` // cleared whenever the email changes
+
+	TOTPSecret   string `json:"-" gorm:"column:totp_secret"`                         // set on enrollment, before it is confirmed
+	TOTPEnabled  bool   `json:"-" gorm:"column:totp_enabled;not null;default:false"` // login requires a second factor
+	TOTPLastStep int64  `json:"-" gorm:"column:totp_last_step;not null;default:0"`   // last accepted time step, so a code cannot be replayed
 
+	ServiceAccount bool `json:"-" gorm:"not null;default:false"` // non-human account that can only authenticate with API tokens
 }
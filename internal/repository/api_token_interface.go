@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+	"time"
+)
+
+type APITokenRepoInterface interface {
+	Create(ctx context.Context, token *model.APIToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*model.APIToken, error)
+	ListByUser(ctx context.Context, userID uint) ([]model.APIToken, error)
+	Revoke(ctx context.Context, userID, id uint) (bool, error)
+	Touch(ctx context.Context, id uint, at time.Time) error
+}
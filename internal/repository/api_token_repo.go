@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APITokenRepo struct {
+	DB *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepoInterface {
+	return &APITokenRepo{DB: db}
+}
+
+func (r *APITokenRepo) Create(ctx context.Context, token *model.APIToken) error {
+	return conn(ctx, r.DB).Create(token).Error
+}
+
+// FindByHash returns the token with tokenHash, or nil if there is none.
+func (r *APITokenRepo) FindByHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	var token model.APIToken
+	if err := conn(ctx, r.DB).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByUser returns every token of the user, revoked ones included, newest first.
+func (r *APITokenRepo) ListByUser(ctx context.Context, userID uint) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := conn(ctx, r.DB).Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// Revoke revokes token id of the user, reporting false if the user has no
+// such token or it was already revoked.
+func (r *APITokenRepo) Revoke(ctx context.Context, userID, id uint) (bool, error) {
+	result := conn(ctx, r.DB).Model(&model.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// Touch records when the token was last used.
+func (r *APITokenRepo) Touch(ctx context.Context, id uint, at time.Time) error {
+	return conn(ctx, r.DB).Model(&model.APIToken{}).Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
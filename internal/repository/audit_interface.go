@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+)
+
+// AuditRepoInterface is append-only: entries can be created and read, never changed.
+type AuditRepoInterface interface {
+	Create(ctx context.Context, entry *model.AuditLog) error
+	List(ctx context.Context, params model.AuditListParams) ([]model.AuditLog, error)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepo struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepoInterface {
+	return &AuditRepo{DB: db}
+}
+
+func (r *AuditRepo) Create(ctx context.Context, entry *model.AuditLog) error {
+	return conn(ctx, r.DB).Create(entry).Error
+}
+
+// List returns the newest entries first, at most params.Limit of them.
+func (r *AuditRepo) List(ctx context.Context, params model.AuditListParams) ([]model.AuditLog, error) {
+	query := conn(ctx, r.DB).Model(&model.AuditLog{})
+
+	if params.ActorUserID != nil {
+		query = query.Where("actor_user_id = ?", *params.ActorUserID)
+	}
+	if params.Action != "" {
+		query = query.Where("action = ?", params.Action)
+	}
+	if params.TargetUserID != nil {
+		query = query.Where("target_user_id = ?", *params.TargetUserID)
+	}
+	if params.From != nil {
+		query = query.Where("created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("created_at < ?", *params.To)
+	}
+	if params.BeforeID > 0 {
+		query = query.Where("id < ?", params.BeforeID)
+	}
+
+	var entries []model.AuditLog
+	err := query.Order("id DESC").Limit(params.Limit).Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+)
+
+type IdempotencyRepoInterface interface {
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, record *model.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
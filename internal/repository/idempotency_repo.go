@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepo struct {
+	DB *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepoInterface {
+	return &IdempotencyRepo{DB: db}
+}
+
+// Reserve inserts record unless an unexpired record with the same key exists.
+// It returns the record now stored under the key and whether it is the one
+// just inserted.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, bool, error) {
+	db := conn(ctx, r.DB)
+
+	if err := db.Where("idempotency_key = ? AND expires_at < ?", record.Key, time.Now()).Delete(&model.IdempotencyRecord{}).Error; err != nil {
+		return nil, false, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return record, true, nil
+	}
+
+	var existing model.IdempotencyRecord
+	if err := db.Where("idempotency_key = ?", record.Key).First(&existing).Error; err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// Complete stores the response to a reserved key, which then expires at
+// record.ExpiresAt rather than at the end of its lease.
+func (r *IdempotencyRepo) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	return conn(ctx, r.DB).Model(&model.IdempotencyRecord{}).
+		Where("idempotency_key = ?", record.Key).
+		Updates(map[string]any{
+			"status_code": record.StatusCode,
+			"headers":     record.Headers,
+			"body":        record.Body,
+			"expires_at":  record.ExpiresAt,
+		}).Error
+}
+
+// Release forgets a key so the request can be retried from scratch.
+func (r *IdempotencyRepo) Release(ctx context.Context, key string) error {
+	return conn(ctx, r.DB).Where("idempotency_key = ?", key).Delete(&model.IdempotencyRecord{}).Error
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	result := conn(ctx, r.DB).Where("expires_at < ?", time.Now()).Delete(&model.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+)
+
+type MFARepoInterface interface {
+	SetTOTPSecret(ctx context.Context, userID uint, secret string) error
+	EnableTOTP(ctx context.Context, userID uint, step int64, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, userID uint) error
+	AdvanceTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MFARepo struct {
+	DB *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepoInterface {
+	return &MFARepo{DB: db}
+}
+
+// SetTOTPSecret stores a pending secret. It only takes effect once EnableTOTP
+// confirms the user can produce codes from it.
+func (r *MFARepo) SetTOTPSecret(ctx context.Context, userID uint, secret string) error {
+	return conn(ctx, r.DB).Model(&model.User{}).Where("id = ? AND totp_enabled = ?", userID, false).
+		UpdateColumns(map[string]any{"totp_secret": secret, "totp_last_step": 0}).Error
+}
+
+// EnableTOTP turns on two-factor login and replaces the user's recovery codes.
+func (r *MFARepo) EnableTOTP(ctx context.Context, userID uint, step int64, recoveryCodeHashes []string) error {
+	return conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", userID).
+			UpdateColumns(map[string]any{"totp_enabled": true, "totp_last_step": step}).Error
+		if err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+}
+
+// DisableTOTP turns off two-factor login and forgets the secret and recovery codes.
+func (r *MFARepo) DisableTOTP(ctx context.Context, userID uint) error {
+	return conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", userID).
+			UpdateColumns(map[string]any{"totp_enabled": false, "totp_secret": "", "totp_last_step": 0}).Error
+		if err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, nil)
+	})
+}
+
+// AdvanceTOTPStep records step as the last accepted one. It reports false if
+// an equal or later step was already accepted, meaning the code is a replay.
+func (r *MFARepo) AdvanceTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := conn(ctx, r.DB).Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		UpdateColumn("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseRecoveryCode marks an unused recovery code as used, reporting whether there was one.
+func (r *MFARepo) UseRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := conn(ctx, r.DB).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountRecoveryCodes returns how many unused recovery codes the user has left.
+func (r *MFARepo) CountRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var n int64
+	err := conn(ctx, r.DB).Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Count(&n).Error
+	return n, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, hashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	codes := make([]model.RecoveryCode, len(hashes))
+	for i, h := range hashes {
+		codes[i] = model.RecoveryCode{UserID: userID, CodeHash: h}
+	}
+	return tx.Create(&codes).Error
+}
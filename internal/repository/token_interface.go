@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+	"time"
+)
+
+type TokenRepoInterface interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id uint) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUserTokens(ctx context.Context, userID uint) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	DeleteExpired(ctx context.Context) error
+}
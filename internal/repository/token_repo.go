@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"go-crud-oapi/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenRepo struct {
+	DB *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepoInterface {
+	return &TokenRepo{DB: db}
+}
+
+func (r *TokenRepo) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	return conn(ctx, r.DB).Create(token).Error
+}
+
+func (r *TokenRepo) FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := conn(ctx, r.DB).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken revokes token id and reports whether this call did so,
+// false meaning a concurrent call revoked it first.
+func (r *TokenRepo) RevokeRefreshToken(ctx context.Context, id uint) (bool, error) {
+	res := conn(ctx, r.DB).Model(&model.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *TokenRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	return conn(ctx, r.DB).Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserTokens revokes every refresh token of a user, ending all their sessions.
+func (r *TokenRepo) RevokeUserTokens(ctx context.Context, userID uint) error {
+	return conn(ctx, r.DB).Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *TokenRepo) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+func (r *TokenRepo) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.DB).Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired removes refresh tokens and revocation entries that can no
+// longer be presented because they are past their expiry.
+func (r *TokenRepo) DeleteExpired(ctx context.Context) error {
+	now := time.Now()
+	if err := conn(ctx, r.DB).Where("expires_at < ?", now).Delete(&model.RefreshToken{}).Error; err != nil {
+		return err
+	}
+	return conn(ctx, r.DB).Where("expires_at < ?", now).Delete(&model.RevokedToken{}).Error
+}
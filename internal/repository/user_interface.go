@@ -2,14 +2,30 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"go-crud-oapi/internal/model"
+	"time"
 )
 
+// ErrVersionConflict is returned when a write expected a version of the row
+// that is no longer current.
+var ErrVersionConflict = errors.New("version conflict")
+
 type UserRepoInterface interface {
 	Create(ctx context.Context, user *model.User) error
-	ListAllUsers(ctx context.Context) ([]model.User, error)
+	ListUsers(ctx context.Context, params model.UserListParams) ([]model.User, error)
 	GetUserById(ctx context.Context, id uint) (*model.User, error)
+	GetUserByIdUnscoped(ctx context.Context, id uint) (*model.User, error)
 	UpdateUser(ctx context.Context, id uint, user *model.User) error
-	DeleteUser(ctx context.Context, id uint) error
+	DeleteUser(ctx context.Context, id uint, version uint) error
+	RestoreUser(ctx context.Context, id uint) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) ([]model.User, error)
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
+	FindByPhone(ctx context.Context, phone string) (*model.User, error)
+	UpdatePassword(ctx context.Context, id uint, hash string) error
+	RecordLoginFailure(ctx context.Context, id uint, lockFor func(failures int) time.Duration) (int, *time.Time, error)
+	ResetLoginFailures(ctx context.Context, id uint) error
+	MarkEmailVerified(ctx context.Context, id uint, email string) (bool, error)
+	CreateServiceAccount(ctx context.Context, user *model.User) error
+	ListServiceAccounts(ctx context.Context) ([]model.User, error)
 }
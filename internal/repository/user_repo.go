@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"go-crud-oapi/internal/model"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,40 +19,185 @@ func NewUserRepository(db *gorm.DB) UserRepoInterface {
 }
 
 func (r *UserRepo) Create(ctx context.Context, user *model.User) error {
-	return r.DB.WithContext(ctx).Create(user).Error
+	return conn(ctx, r.DB).Create(user).Error
 }
 
 func (r *UserRepo) GetUserById(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	if err := r.DB.WithContext(ctx).First(&user, id).Error; err != nil {
+	if err := conn(ctx, r.DB).First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+// GetUserByIdUnscoped also finds soft-deleted users.
+func (r *UserRepo) GetUserByIdUnscoped(ctx context.Context, id uint) (*model.User, error) {
+	var user model.User
+	if err := conn(ctx, r.DB).Unscoped().First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser overwrites every profile column of user id, zero values
+// included, and bumps its version. The password column is only written when
+// user.Password is set. A non-zero user.Version must match the stored one or
+// ErrVersionConflict is returned.
 func (r *UserRepo) UpdateUser(ctx context.Context, id uint, user *model.User) error {
-	return r.DB.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(user).Error
+	updates := map[string]any{
+		"name":    user.Name,
+		"email":   user.Email,
+		"phone":   user.Phone,
+		"age":     user.Age,
+		"role":    user.Role,
+		"version": gorm.Expr("version + 1"),
+
+		"email_verified_at": user.EmailVerifiedAt,
+	}
+	if user.Password != "" {
+		updates["password"] = user.Password
+	}
+
+	query := conn(ctx, r.DB).Model(&model.User{}).Where("id = ?", id)
+	if user.Version != 0 {
+		query = query.Where("version = ?", user.Version)
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if user.Version != 0 {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteUser soft-deletes user id. A non-zero version must match the stored one.
+func (r *UserRepo) DeleteUser(ctx context.Context, id uint, version uint) error {
+	query := conn(ctx, r.DB)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&model.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if version != 0 {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (r *UserRepo) DeleteUser(ctx context.Context, id uint) error {
-	if err := r.DB.WithContext(ctx).Delete(&model.User{}, id).Error; err != nil {
-		return err
+// RestoreUser clears deleted_at on a soft-deleted user.
+func (r *UserRepo) RestoreUser(ctx context.Context, id uint) error {
+	result := conn(ctx, r.DB).Unscoped().Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return result.Error
 	}
-	if r.DB.RowsAffected == 0 {
+	if result.RowsAffected == 0 {
 		return gorm.ErrRecordNotFound
 	}
 	return nil
 }
 
-func (r *UserRepo) ListAllUsers(ctx context.Context) ([]model.User, error) {
+// PurgeDeletedBefore permanently removes users soft-deleted before cutoff,
+// together with their refresh tokens, API tokens and recovery codes, and
+// returns the users it removed.
+func (r *UserRepo) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) ([]model.User, error) {
 	var users []model.User
-	err := r.DB.WithContext(ctx).Find(&users).Error
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Find(&users).Error; err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return nil
+		}
+		ids := make([]uint, 0, len(users))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		// Nothing cascades from users, so their own rows go first.
+		for _, owned := range []any{&model.RefreshToken{}, &model.APIToken{}, &model.RecoveryCode{}} {
+			if err := tx.Where("user_id IN ?", ids).Delete(owned).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&model.User{}, ids).Error
+	})
+	return users, err
+}
+
+// ListUsers returns at most params.Limit users using keyset pagination, so the
+// cost of a page does not grow with its offset.
+func (r *UserRepo) ListUsers(ctx context.Context, params model.UserListParams) ([]model.User, error) {
+	query := conn(ctx, r.DB).Model(&model.User{})
+	if params.IncludeDeleted {
+		query = query.Unscoped()
+	}
+
+	if params.Role != "" {
+		query = query.Where("role = ?", params.Role)
+	}
+	if params.MinAge != nil {
+		query = query.Where("age >= ?", *params.MinAge)
+	}
+	if params.MaxAge != nil {
+		query = query.Where("age <= ?", *params.MaxAge)
+	}
+	if params.Email != "" {
+		query = query.Where("LOWER(email) LIKE ?", "%"+strings.ToLower(params.Email)+"%")
+	}
+	if params.Name != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(params.Name)+"%")
+	}
+
+	column := params.SortBy
+	switch column {
+	case "":
+		column = "id"
+	case "id", "name", "email", "age":
+	default:
+		return nil, fmt.Errorf("unsupported sort column %q", column)
+	}
+	dir, cmp := "ASC", ">"
+	if params.SortDesc {
+		dir, cmp = "DESC", "<"
+	}
+
+	if params.After != nil {
+		if column == "id" {
+			query = query.Where("id "+cmp+" ?", params.After.ID)
+		} else {
+			query = query.Where(
+				"("+column+" "+cmp+" ?) OR ("+column+" = ? AND id "+cmp+" ?)",
+				params.After.Value, params.After.Value, params.After.ID,
+			)
+		}
+	}
+
+	if column != "id" {
+		query = query.Order(column + " " + dir)
+	}
+	query = query.Order("id " + dir)
+
+	var users []model.User
+	err := query.Limit(params.Limit).Find(&users).Error
 	return users, err
 }
 
 func (r *UserRepo) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	if err := r.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+	if err := conn(ctx, r.DB).Where("email = ?", email).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil // Email not found, it's okay
 		}
@@ -57,3 +205,76 @@ func (r *UserRepo) FindByEmail(ctx context.Context, email string) (*model.User,
 	}
 	return &user, nil // Email found
 }
+
+// FindByPhone returns the user with phone, or nil if there is none.
+func (r *UserRepo) FindByPhone(ctx context.Context, phone string) (*model.User, error) {
+	var user model.User
+	if err := conn(ctx, r.DB).Where("phone = ?", phone).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepo) UpdatePassword(ctx context.Context, id uint, hash string) error {
+	return conn(ctx, r.DB).Model(&model.User{}).Where("id = ?", id).Update("password", hash).Error
+}
+
+// RecordLoginFailure counts a failed login and locks the account for
+// lockFor(failures), where failures is the count including this one. The
+// increment and the lock happen in one transaction holding the row, so
+// concurrent failures each see their own count and none skips the lock. It
+// does not bump the version, since the profile itself is unchanged.
+func (r *UserRepo) RecordLoginFailure(ctx context.Context, id uint, lockFor func(failures int) time.Duration) (int, *time.Time, error) {
+	var failures int
+	var lockedUntil *time.Time
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.User{}).Where("id = ?", id).
+			UpdateColumn("failed_logins", gorm.Expr("failed_logins + 1")).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&model.User{}).Where("id = ?", id).Pluck("failed_logins", &failures).Error; err != nil {
+			return err
+		}
+		d := lockFor(failures)
+		if d <= 0 {
+			return nil
+		}
+		until := time.Now().Add(d)
+		lockedUntil = &until
+		return tx.Model(&model.User{}).Where("id = ?", id).UpdateColumn("locked_until", until).Error
+	})
+	return failures, lockedUntil, err
+}
+
+// ResetLoginFailures clears the failure count and any lock.
+func (r *UserRepo) ResetLoginFailures(ctx context.Context, id uint) error {
+	return conn(ctx, r.DB).Model(&model.User{}).Where("id = ?", id).
+		UpdateColumns(map[string]any{"failed_logins": 0, "locked_until": nil}).Error
+}
+
+// MarkEmailVerified records that email was verified for user id. It reports
+// false if the user's email has changed since or was already verified.
+func (r *UserRepo) MarkEmailVerified(ctx context.Context, id uint, email string) (bool, error) {
+	result := conn(ctx, r.DB).Model(&model.User{}).
+		Where("id = ? AND email = ? AND email_verified_at IS NULL", id, email).
+		UpdateColumn("email_verified_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// CreateServiceAccount inserts a service account. They have no phone number,
+// so the column is left NULL rather than colliding on the unique index.
+func (r *UserRepo) CreateServiceAccount(ctx context.Context, user *model.User) error {
+	user.ServiceAccount = true
+	return conn(ctx, r.DB).Omit("Phone").Create(user).Error
+}
+
+// ListServiceAccounts returns every service account, oldest first.
+func (r *UserRepo) ListServiceAccounts(ctx context.Context) ([]model.User, error) {
+	var users []model.User
+	err := conn(ctx, r.DB).Where("service_account = ?", true).Order("id").Find(&users).Error
+	return users, err
+}
@@ -1,31 +1,164 @@
 package router
 
 import (
+	"fmt"
 	"go-crud-oapi/internal/controller"
+	"go-crud-oapi/internal/handler"
 	"go-crud-oapi/internal/middleware"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/problem"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 )
 
-func NewRouter(userController *controller.UserController, authCtrl *controller.AuthController) http.Handler {
+// ResetLimits cap requests to the password reset endpoints per client IP and
+// per email address asked for.
+type ResetLimits struct {
+	IP    *middleware.RequestLimit
+	Email *middleware.RequestLimit
+}
+
+// NewRouter serves server through the handler generated from
+// api/openapi.yaml, which decodes path, query and header parameters. Routes
+// are listed here so that each can carry its own middleware; NewRouter
+// panics if they differ from the operations in the spec. openAPI checks each
+// request against the spec once the caller is authenticated, so that anyone
+// else gets a 401 whatever they send. The spec itself and its Swagger UI are
+// served by docs, outside the checked routes, as is the password reset page.
+func NewRouter(server *controller.Server, docs *controller.DocsController, openAPI *middleware.OpenAPIValidator, jwtAuth *middleware.JWTAuth, requireMFA func(http.Handler) http.Handler, idempotency *middleware.Idempotency, loginThrottle *middleware.LoginThrottle, resetLimits ResetLimits) http.Handler {
 	r := chi.NewRouter()
+	api := &handler.ServerInterfaceWrapper{Handler: server, ErrorHandlerFunc: controller.ParamError}
 
 	r.Use(chiMiddleware.Recoverer)
 	r.Use(middleware.RequestID)
+	r.NotFound(notFound)
+	r.MethodNotAllowed(methodNotAllowed(r))
+
+	r.Group(func(r chi.Router) {
+		r.Use(openAPI.Middleware)
+
+		r.Get("/.well-known/jwks.json", api.GetJWKS)
+		r.With(loginThrottle.Middleware).Post("/login", api.Login)
+		r.With(loginThrottle.Middleware).Post("/login/mfa", api.VerifyMFA)
+		r.Post("/token/refresh", api.RefreshToken)
+
+		resetByIP := resetLimits.IP.Middleware("password_reset_ip", middleware.ClientIP)
+		resetByEmail := resetLimits.Email.Middleware("password_reset_email", middleware.BodyEmail)
+		r.With(resetByIP, resetByEmail).Post("/password/forgot", api.ForgotPassword)
+		r.With(resetByIP).Post("/password/reset", api.ResetPassword)
+		r.Get("/verify-email", api.VerifyEmail)
+	})
+
+	r.With(jwtAuth.Middleware, middleware.RequireSession, openAPI.Middleware).Post("/logout", api.Logout)
 
-	r.Post("/login", authCtrl.Login)
+	// Self-service routes for the authenticated user. These stay reachable
+	// without a second factor so that users can enroll one. API tokens may
+	// read the profile but not change the account.
+	r.Route("/me", func(r chi.Router) {
+		r.Use(jwtAuth.Middleware, openAPI.Middleware)
+		r.Get("/", api.GetMe)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireSession)
+			r.Patch("/", api.PatchMe)
+			r.Post("/password", api.ChangeMyPassword)
+			r.Post("/verify-email", api.ResendVerification)
+
+			r.Post("/mfa/totp", api.EnrollTOTP)
+			r.Post("/mfa/totp/verify", api.ConfirmTOTP)
+			r.Delete("/mfa/totp", api.DisableTOTP)
+
+			r.Get("/tokens", api.ListMyTokens)
+			r.Post("/tokens", api.CreateMyToken)
+			r.Delete("/tokens/{tokenID}", api.RevokeMyToken)
+		})
+	})
 
 	// User routes
 	r.Route("/users", func(r chi.Router) {
-		r.Get("/", userController.ListUsers)
-		r.Get("/{id}", userController.GetUser)
+		r.Use(jwtAuth.Middleware, requireMFA, openAPI.Middleware)
+
+		r.With(middleware.RequirePermission(auth.PermUsersRead)).Get("/", api.ListUsers)
+		r.With(middleware.RequirePermission(auth.PermUsersRead)).Get("/{id}", api.GetUser)
+
+		r.With(middleware.RequirePermission(auth.PermUsersWrite), idempotency.Middleware).Post("/", api.CreateUser)
+		r.With(middleware.RequirePermission(auth.PermUsersWrite)).Put("/{id}", api.UpdateUser)
+		r.With(middleware.RequirePermission(auth.PermUsersWrite)).Patch("/{id}", api.PatchUser)
+		r.With(middleware.RequirePermission(auth.PermUsersDelete)).Delete("/{id}", api.DeleteUser)
+		r.With(middleware.RequirePermission(auth.PermUsersWrite)).Post("/{id}/password", api.ChangePassword)
+		r.With(middleware.RequirePermission(auth.PermUsersRestore)).Post("/{id}/restore", api.RestoreUser)
+		r.With(middleware.RequirePermission(auth.PermUsersWrite)).Post("/{id}/unlock", api.UnlockUser)
+
+		r.With(middleware.RequirePermission(auth.PermTokensManage)).Get("/{id}/tokens", api.ListUserTokens)
+		r.With(middleware.RequirePermission(auth.PermTokensManage), middleware.RequireSession).Post("/{id}/tokens", api.CreateUserToken)
+		r.With(middleware.RequirePermission(auth.PermTokensManage)).Delete("/{id}/tokens/{tokenID}", api.RevokeUserToken)
+	})
 
-		r.With(middleware.JWTAuthMiddleware).Post("/", userController.CreateUser)
-		r.With(middleware.JWTAuthMiddleware).Put("/{id}", userController.UpdateUser)
-		r.With(middleware.JWTAuthMiddleware).Delete("/{id}", userController.DeleteUser)
+	r.Route("/service-accounts", func(r chi.Router) {
+		r.Use(jwtAuth.Middleware, requireMFA, openAPI.Middleware, middleware.RequirePermission(auth.PermTokensManage))
+		r.Get("/", api.ListServiceAccounts)
+		r.Post("/", api.CreateServiceAccount)
 	})
 
+	r.With(jwtAuth.Middleware, requireMFA, openAPI.Middleware, middleware.RequirePermission(auth.PermAuditRead)).Get("/audit", api.ListAuditLogs)
+
+	mustMatchSpec(r, server)
+
+	// The page the password reset email links to; it posts to the route above.
+	r.Get("/password/reset", server.ResetPasswordPage)
+
+	r.Get(controller.SpecPath, docs.OpenAPISpec)
+	r.Get("/docs", http.RedirectHandler("/docs/", http.StatusMovedPermanently).ServeHTTP)
+	r.Get("/docs/swagger-initializer.js", docs.SwaggerInitializer)
+	r.Get("/docs/*", docs.SwaggerUI)
 	return r
 }
+
+func notFound(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, problem.New(problem.TypeNotFound, http.StatusNotFound, "no resource at "+r.URL.Path))
+}
+
+// methodNotAllowed answers for a path that routes serves with other methods,
+// which it lists in the Allow header as chi's own 405 does.
+func methodNotAllowed(routes chi.Routes) http.HandlerFunc {
+	methods := []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, m := range methods {
+			if routes.Match(chi.NewRouteContext(), m, r.URL.Path) {
+				w.Header().Add("Allow", m)
+			}
+		}
+		problem.Write(w, r, problem.Status(http.StatusMethodNotAllowed, r.Method+" is not supported for "+r.URL.Path))
+	}
+}
+
+// mustMatchSpec panics unless r serves exactly the routes the generated
+// handler would register for si, so that a route cannot be added, dropped or
+// misspelled here without the spec changing too.
+func mustMatchSpec(r chi.Routes, si handler.ServerInterface) {
+	spec := handler.HandlerFromMux(si, chi.NewRouter()).(chi.Routes)
+
+	want, got := routeList(spec), routeList(r)
+	if !slices.Equal(want, got) {
+		panic(fmt.Sprintf("router does not match api/openapi.yaml:\n spec:   %v\n router: %v", want, got))
+	}
+}
+
+// routeList returns "METHOD /pattern" for every route, sorted, ignoring the
+// trailing slash chi adds to the root of a sub-router.
+func routeList(r chi.Routes) []string {
+	var list []string
+	chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		list = append(list, method+" "+route)
+		return nil
+	})
+	slices.Sort(list)
+	return list
+}
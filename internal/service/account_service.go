@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/mailer"
+	"go-crud-oapi/pkg/validation"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidResetToken is returned for a password reset token that is
+// malformed, expired, meant for something else or already used.
+var ErrInvalidResetToken = &InvalidRequestError{Reason: "the reset token is invalid or has expired"}
+
+// ErrInvalidVerificationToken is returned for an email verification token
+// that is malformed, expired, meant for something else or already used.
+var ErrInvalidVerificationToken = &InvalidRequestError{Reason: "the verification token is invalid or has expired"}
+
+// ErrEmailAlreadyVerified is returned when asking to verify an address that
+// already is.
+var ErrEmailAlreadyVerified = &ConflictError{Resource: "user", Field: "email", Reason: "email already verified"}
+
+type AccountServiceInterface interface {
+	ForgotPassword(ctx context.Context, req model.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req model.ResetPasswordRequest) error
+	SendVerification(ctx context.Context, user *model.User) error
+	VerifyEmail(ctx context.Context, token string) error
+}
+
+// AccountTTLs are the lifetimes of the links sent by email.
+type AccountTTLs struct {
+	PasswordReset time.Duration
+	VerifyEmail   time.Duration
+}
+
+type AccountService struct {
+	users   repository.UserRepoInterface
+	tokens  repository.TokenRepoInterface
+	hasher  *auth.PasswordHasher
+	keys    *auth.KeyManager
+	mail    mailer.Mailer
+	tx      repository.Transactor
+	audit   AuditServiceInterface
+	baseURL string
+	ttls    AccountTTLs
+}
+
+func NewAccountService(users repository.UserRepoInterface, tokens repository.TokenRepoInterface, hasher *auth.PasswordHasher, keys *auth.KeyManager, mail mailer.Mailer, tx repository.Transactor, audit AuditServiceInterface, baseURL string, ttls AccountTTLs) AccountServiceInterface {
+	return &AccountService{users: users, tokens: tokens, hasher: hasher, keys: keys, mail: mail, tx: tx, audit: audit, baseURL: baseURL, ttls: ttls}
+}
+
+// ForgotPassword emails a password reset link if email belongs to a user. It
+// succeeds either way so that callers cannot tell which emails exist: the
+// email is sent after returning, so known addresses are answered as fast as
+// unknown ones, and a failure to send is logged rather than returned.
+func (s *AccountService) ForgotPassword(ctx context.Context, req model.ForgotPasswordRequest) error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	user, err := s.users.FindByEmail(ctx, req.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.ServiceAccount {
+		logger.L(ctx).Info("Password reset requested for unknown email")
+		return nil
+	}
+
+	go s.sendPasswordReset(context.WithoutCancel(ctx), user)
+	return nil
+}
+
+func (s *AccountService) sendPasswordReset(ctx context.Context, user *model.User) {
+	token, err := s.keys.GenerateActionToken(user.ID, auth.TokenUsePasswordReset, passwordBinding(user), s.ttls.PasswordReset)
+	if err == nil {
+		err = s.mail.Send(ctx, mailer.Message{
+			To:      user.Email,
+			Subject: "Reset your password",
+			Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %s and works once.\n\n%s\n\nIf you did not ask for this, you can ignore this email.\n",
+				user.Name, s.ttls.PasswordReset, s.link("/password/reset", token)),
+		})
+	}
+	if err != nil {
+		logger.L(ctx).Error("Failed to send password reset email", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword. Every
+// session of the user is ended and any lockout is cleared.
+func (s *AccountService) ResetPassword(ctx context.Context, req model.ResetPasswordRequest) error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	id, binding, err := s.keys.ParseActionToken(req.Token, auth.TokenUsePasswordReset)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	user, err := s.users.GetUserById(ctx, id)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	// The binding covers the current hash, so the token dies once the password changes.
+	if binding != passwordBinding(user) {
+		return ErrInvalidResetToken
+	}
+
+	hash, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	after := *user
+	after.Password = hash
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.users.UpdatePassword(ctx, id, hash); err != nil {
+			return err
+		}
+		if err := s.users.ResetLoginFailures(ctx, id); err != nil {
+			return err
+		}
+		if err := s.tokens.RevokeUserTokens(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditActionPasswordReset, id, user, &after)
+	})
+}
+
+// SendVerification emails a link that confirms the user owns their address.
+// It returns ErrEmailAlreadyVerified if there is nothing left to confirm.
+func (s *AccountService) SendVerification(ctx context.Context, user *model.User) error {
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := s.keys.GenerateActionToken(user.ID, auth.TokenUseVerifyEmail, user.Email, s.ttls.VerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	return s.mail.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below within %s.\n\n%s\n",
+			user.Name, s.ttls.VerifyEmail, s.link("/verify-email", token)),
+	})
+}
+
+// VerifyEmail marks the address named in token as verified, provided it is
+// still the user's address and was not verified already.
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	id, email, err := s.keys.ParseActionToken(token, auth.TokenUseVerifyEmail)
+	if err != nil || email == "" {
+		return ErrInvalidVerificationToken
+	}
+	user, err := s.users.GetUserById(ctx, id)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	after := *user
+	verifiedAt := time.Now()
+	after.EmailVerifiedAt = &verifiedAt
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		ok, err := s.users.MarkEmailVerified(ctx, id, email)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidVerificationToken
+		}
+		return s.audit.Record(ctx, model.AuditActionEmailVerify, id, user, &after)
+	})
+}
+
+func (s *AccountService) link(path, token string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// passwordBinding ties a reset token to the password hash it was issued for.
+func passwordBinding(user *model.User) string {
+	return auth.HashToken(user.Password)[:16]
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/validation"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidAPIToken is returned for an API token that is unknown, revoked,
+// expired or whose owner no longer exists.
+var ErrInvalidAPIToken = &UnauthorizedError{Reason: "invalid api token"}
+
+// ErrAPITokenNotFound is returned when revoking a token the user does not have.
+var ErrAPITokenNotFound = &NotFoundError{Resource: "api token"}
+
+// serviceAccountDomain is used for the generated emails of service accounts.
+// The .invalid TLD guarantees nothing is ever delivered to them.
+const serviceAccountDomain = "service-accounts.invalid"
+
+type APITokenServiceInterface interface {
+	Create(ctx context.Context, userID uint, req model.CreateAPITokenRequest, mfa bool) (*model.APIToken, string, error)
+	List(ctx context.Context, userID uint) ([]model.APIToken, error)
+	Revoke(ctx context.Context, userID, tokenID uint) error
+	AuthenticateAPIToken(ctx context.Context, token string) (*model.User, *model.APIToken, error)
+	CreateServiceAccount(ctx context.Context, req model.CreateServiceAccountRequest) (*model.User, error)
+	ListServiceAccounts(ctx context.Context) ([]model.User, error)
+}
+
+type APITokenService struct {
+	users repository.UserRepoInterface
+	repo  repository.APITokenRepoInterface
+	tx    repository.Transactor
+	audit AuditServiceInterface
+}
+
+func NewAPITokenService(users repository.UserRepoInterface, repo repository.APITokenRepoInterface, tx repository.Transactor, audit AuditServiceInterface) APITokenServiceInterface {
+	return &APITokenService{users: users, repo: repo, tx: tx, audit: audit}
+}
+
+// Create issues a token for user userID and returns it together with the
+// secret, which is not stored and cannot be shown again. Scopes must be
+// permissions of the user's role. mfa records whether the caller's session
+// passed a second factor; the token inherits it.
+func (s *APITokenService) Create(ctx context.Context, userID uint, req model.CreateAPITokenRequest, mfa bool) (*model.APIToken, string, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.users.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, "", userError(err)
+	}
+
+	for _, scope := range req.Scopes {
+		if !auth.HasPermission(user.Role, auth.Permission(scope)) {
+			return nil, "", &validation.Error{Fields: []validation.FieldError{{
+				Field:   "scopes",
+				Rule:    "scope",
+				Message: fmt.Sprintf("%q is not a permission of the %s role", scope, user.Role),
+			}}}
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", &validation.Error{Fields: []validation.FieldError{{
+			Field:   "expires_at",
+			Rule:    "future",
+			Message: "must be in the future",
+		}}}
+	}
+
+	secret, prefix, hash, err := auth.GenerateAPIToken()
+	if err != nil {
+		return nil, "", err
+	}
+	token := &model.APIToken{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		TokenHash: hash,
+		Scopes:    strings.Join(req.Scopes, ","),
+		MFA:       mfa,
+		ExpiresAt: req.ExpiresAt,
+	}
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, token); err != nil {
+			return err
+		}
+		return s.audit.RecordChanges(ctx, model.AuditActionTokenCreate, userID, map[string]model.FieldChange{
+			"api_token": {New: token.ID},
+			"scopes":    {New: req.Scopes},
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// List returns every token of user userID, revoked ones included.
+func (s *APITokenService) List(ctx context.Context, userID uint) ([]model.APIToken, error) {
+	if _, err := s.users.GetUserById(ctx, userID); err != nil {
+		return nil, userError(err)
+	}
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Revoke stops token tokenID of user userID from being accepted.
+func (s *APITokenService) Revoke(ctx context.Context, userID, tokenID uint) error {
+	if _, err := s.users.GetUserById(ctx, userID); err != nil {
+		return userError(err)
+	}
+
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		revoked, err := s.repo.Revoke(ctx, userID, tokenID)
+		if err != nil {
+			return err
+		}
+		if !revoked {
+			return ErrAPITokenNotFound
+		}
+		return s.audit.RecordChanges(ctx, model.AuditActionTokenRevoke, userID, map[string]model.FieldChange{
+			"api_token": {Old: tokenID},
+		})
+	})
+}
+
+// AuthenticateAPIToken resolves a bearer token to its owner and records its use.
+func (s *APITokenService) AuthenticateAPIToken(ctx context.Context, secret string) (*model.User, *model.APIToken, error) {
+	token, err := s.repo.FindByHash(ctx, auth.HashToken(secret))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if token == nil || token.RevokedAt != nil || (token.ExpiresAt != nil && !token.ExpiresAt.After(now)) {
+		return nil, nil, ErrInvalidAPIToken
+	}
+
+	user, err := s.users.GetUserById(ctx, token.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrInvalidAPIToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.repo.Touch(ctx, token.ID, now); err != nil {
+		logger.L(ctx).Warn("Failed to record API token use", zap.Uint("token_id", token.ID), zap.Error(err))
+	}
+	return user, token, nil
+}
+
+// CreateServiceAccount creates a non-human user. It has no password or phone
+// and a generated email, so it can only authenticate with API tokens.
+func (s *APITokenService) CreateServiceAccount(ctx context.Context, req model.CreateServiceAccountRequest) (*model.User, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		Name:  req.Name,
+		Email: uuid.NewString() + "@" + serviceAccountDomain,
+		Role:  req.Role,
+	}
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.users.CreateServiceAccount(ctx, user); err != nil {
+			return userError(err)
+		}
+		return s.audit.Record(ctx, model.AuditActionCreate, user.ID, nil, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *APITokenService) ListServiceAccounts(ctx context.Context) ([]model.User, error) {
+	return s.users.ListServiceAccounts(ctx)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/pkg/requestctx"
+	"strconv"
+	"time"
+)
+
+// redacted stands in for secret values in audit diffs.
+const redacted = "<redacted>"
+
+type AuditServiceInterface interface {
+	Record(ctx context.Context, action string, targetUserID uint, before, after *model.User) error
+	RecordChanges(ctx context.Context, action string, targetUserID uint, changes map[string]model.FieldChange) error
+	List(ctx context.Context, params model.AuditListParams, cursor string) (*model.AuditPage, error)
+}
+
+type AuditService struct {
+	repo repository.AuditRepoInterface
+}
+
+func NewAuditService(repo repository.AuditRepoInterface) AuditServiceInterface {
+	return &AuditService{repo: repo}
+}
+
+// Record appends an audit entry for a user mutation. before is nil for a
+// create and after is nil for a delete. The actor and request ID are taken
+// from ctx.
+func (s *AuditService) Record(ctx context.Context, action string, targetUserID uint, before, after *model.User) error {
+	return s.RecordChanges(ctx, action, targetUserID, diffUsers(before, after))
+}
+
+// RecordChanges appends an audit entry for a mutation of something the user
+// owns rather than the user row itself, such as an API token.
+func (s *AuditService) RecordChanges(ctx context.Context, action string, targetUserID uint, changes map[string]model.FieldChange) error {
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	entry := &model.AuditLog{
+		Actor:        model.AuditActorSystem,
+		Action:       action,
+		TargetUserID: targetUserID,
+		Changes:      string(encoded),
+		RequestID:    requestctx.RequestID(ctx),
+	}
+	if caller := requestctx.Actor(ctx); caller.UserID != 0 {
+		entry.Actor = model.AuditActorUser
+		entry.ActorUserID = &caller.UserID
+		if caller.APITokenID != 0 {
+			entry.ActorTokenID = &caller.APITokenID
+		}
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+// List returns one page of audit entries, newest first. cursor is the
+// NextCursor of the previous page.
+func (s *AuditService) List(ctx context.Context, params model.AuditListParams, cursor string) (*model.AuditPage, error) {
+	if params.Limit <= 0 {
+		params.Limit = DefaultPageSize
+	}
+	if params.Limit > MaxPageSize {
+		params.Limit = MaxPageSize
+	}
+
+	if cursor != "" {
+		id, err := strconv.ParseUint(cursor, 36, 64)
+		if err != nil || id == 0 {
+			return nil, ErrInvalidCursor
+		}
+		params.BeforeID = uint(id)
+	}
+
+	requested := params.Limit
+	params.Limit++
+	entries, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.AuditPage{Items: entries}
+	if len(entries) > requested {
+		page.Items = entries[:requested]
+		page.NextCursor = strconv.FormatUint(uint64(page.Items[requested-1].ID), 36)
+	}
+	return page, nil
+}
+
+// diffUsers returns the audited fields that differ between before and after.
+// Password hashes are never written to the log, only the fact they changed.
+func diffUsers(before, after *model.User) map[string]model.FieldChange {
+	var b, a model.User
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	changes := map[string]model.FieldChange{}
+	add := func(field string, old, new any, changed bool) {
+		if !changed {
+			return
+		}
+		c := model.FieldChange{}
+		if before != nil {
+			c.Old = old
+		}
+		if after != nil {
+			c.New = new
+		}
+		changes[field] = c
+	}
+
+	add("name", b.Name, a.Name, b.Name != a.Name)
+	add("email", b.Email, a.Email, b.Email != a.Email)
+	add("phone", b.Phone, a.Phone, b.Phone != a.Phone)
+	add("age", b.Age, a.Age, b.Age != a.Age || before == nil || after == nil)
+	add("role", b.Role, a.Role, b.Role != a.Role)
+	add("password", redacted, redacted, b.Password != a.Password)
+
+	// State that only changes on an existing user, through its own action.
+	if before != nil && after != nil {
+		add("deleted", b.DeletedAt.Valid, a.DeletedAt.Valid, b.DeletedAt.Valid != a.DeletedAt.Valid)
+		add("mfa_enabled", b.TOTPEnabled, a.TOTPEnabled, b.TOTPEnabled != a.TOTPEnabled)
+		add("email_verified", b.EmailVerifiedAt != nil, a.EmailVerifiedAt != nil, (b.EmailVerifiedAt == nil) != (a.EmailVerifiedAt == nil))
+		add("failed_logins", b.FailedLogins, a.FailedLogins, b.FailedLogins != a.FailedLogins)
+		add("locked_until", timeOrNil(b.LockedUntil), timeOrNil(a.LockedUntil), !sameTime(b.LockedUntil, a.LockedUntil))
+	}
+	return changes
+}
+
+// timeOrNil unwraps t so that an unset time is left out of a FieldChange.
+func timeOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/logger"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidRefreshToken is returned for unknown, expired or reused refresh tokens.
+var ErrInvalidRefreshToken = &UnauthorizedError{Reason: "invalid refresh token"}
+
+// ErrMFARequired is returned when tokens are requested for a user who has not
+// yet passed their second factor.
+var ErrMFARequired = &UnauthorizedError{Reason: "two-factor authentication required"}
+
+// ErrInvalidCredentials is returned for every failed login, whether the email
+// is unknown, the password is wrong or the account is locked, so that callers
+// cannot tell which accounts exist.
+var ErrInvalidCredentials = &UnauthorizedError{Reason: "invalid email or password"}
+
+// ErrMFAFailed is returned for every failed second login step, whether the
+// challenge token or the code was wrong.
+var ErrMFAFailed = &UnauthorizedError{Reason: "invalid or expired two-factor code"}
+
+type AuthServiceInterface interface {
+	Authenticate(ctx context.Context, email, password string) (*model.User, error)
+	IssueTokens(ctx context.Context, user *model.User) (*model.AuthResponse, error)
+	BeginMFA(ctx context.Context, user *model.User) (*model.MFAChallengeResponse, error)
+	CompleteMFA(ctx context.Context, req model.MFALoginRequest) (*model.AuthResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*model.AuthResponse, error)
+	Logout(ctx context.Context, refreshToken string, accessJTI string, accessExpiry time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type AuthService struct {
+	users      repository.UserRepoInterface
+	tokens     repository.TokenRepoInterface
+	hasher     *auth.PasswordHasher
+	keys       *auth.KeyManager
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	lockout    auth.Backoff
+	mfa        MFAServiceInterface
+	mfaTTL     time.Duration
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewAuthService(users repository.UserRepoInterface, tokens repository.TokenRepoInterface, hasher *auth.PasswordHasher, keys *auth.KeyManager, accessTTL, refreshTTL time.Duration, lockout auth.Backoff, mfa MFAServiceInterface, mfaTTL time.Duration) AuthServiceInterface {
+	return &AuthService{users: users, tokens: tokens, hasher: hasher, keys: keys, accessTTL: accessTTL, refreshTTL: refreshTTL, lockout: lockout, mfa: mfa, mfaTTL: mfaTTL}
+}
+
+// Authenticate checks an email and password. Consecutive failures lock the
+// account with exponential backoff. A hash made with an outdated algorithm or
+// cost is transparently replaced while the plaintext is at hand.
+func (s *AuthService) Authenticate(ctx context.Context, email, password string) (*model.User, error) {
+	log := logger.L(ctx)
+
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.ServiceAccount {
+		// Spend as long as a real check so response times do not reveal unknown
+		// emails. Service accounts have no password and only use API tokens.
+		s.hasher.Verify(s.fakeHash(), password)
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		log.Warn("Login attempt on locked account", zap.Uint("user_id", user.ID), zap.Time("locked_until", *user.LockedUntil))
+		// Checking no hash would answer locked accounts measurably faster.
+		s.hasher.Verify(s.fakeHash(), password)
+		return nil, ErrInvalidCredentials
+	}
+
+	ok, needsRehash, err := s.hasher.Verify(user.Password, password)
+	if errors.Is(err, auth.ErrUnknownHashFormat) {
+		// Rows stored before passwords were hashed can never match; an admin
+		// has to reset them. Answer like any other wrong password.
+		log.Warn("Stored password has an unknown hash format", zap.Uint("user_id", user.ID))
+		ok, err = false, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.recordFailure(ctx, user)
+		return nil, ErrInvalidCredentials
+	}
+
+	// With TOTP on, failures are only cleared once the second factor passes, so
+	// a known password cannot be used to reset the backoff between code guesses.
+	if !user.TOTPEnabled && (user.FailedLogins > 0 || user.LockedUntil != nil) {
+		if err := s.users.ResetLoginFailures(ctx, user.ID); err != nil {
+			log.Error("Failed to reset login failures", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}
+
+	if needsRehash {
+		if hash, err := s.hasher.Hash(password); err == nil {
+			if err := s.users.UpdatePassword(ctx, user.ID, hash); err == nil {
+				user.Password = hash
+			}
+		}
+	}
+
+	return user, nil
+}
+
+// IssueTokens starts a new refresh token family for a freshly authenticated
+// user. Users with TOTP enabled must go through BeginMFA and CompleteMFA.
+func (s *AuthService) IssueTokens(ctx context.Context, user *model.User) (*model.AuthResponse, error) {
+	if user.TOTPEnabled {
+		return nil, ErrMFARequired
+	}
+	return s.issue(ctx, user, uuid.New().String())
+}
+
+// BeginMFA returns the challenge token for the second login step.
+func (s *AuthService) BeginMFA(ctx context.Context, user *model.User) (*model.MFAChallengeResponse, error) {
+	token, err := s.keys.GenerateMFAToken(user.ID, s.mfaTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &model.MFAChallengeResponse{MFARequired: true, MFAToken: token, ExpiresIn: int64(s.mfaTTL.Seconds())}, nil
+}
+
+// CompleteMFA checks the second factor for a challenge token and starts a new
+// refresh token family. Wrong codes count towards the account lockout.
+func (s *AuthService) CompleteMFA(ctx context.Context, req model.MFALoginRequest) (*model.AuthResponse, error) {
+	userID, err := s.keys.ParseMFAToken(req.MFAToken)
+	if err != nil {
+		return nil, ErrMFAFailed
+	}
+	user, err := s.users.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, ErrMFAFailed
+	}
+
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		logger.L(ctx).Warn("MFA attempt on locked account", zap.Uint("user_id", user.ID), zap.Time("locked_until", *user.LockedUntil))
+		return nil, ErrMFAFailed
+	}
+
+	err = s.mfa.Verify(ctx, user, req.Code, req.RecoveryCode)
+	if errors.Is(err, ErrInvalidMFACode) || errors.Is(err, ErrMFANotEnrolled) {
+		s.recordFailure(ctx, user)
+		return nil, ErrMFAFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.FailedLogins > 0 || user.LockedUntil != nil {
+		if err := s.users.ResetLoginFailures(ctx, user.ID); err != nil {
+			logger.L(ctx).Error("Failed to reset login failures", zap.Uint("user_id", user.ID), zap.Error(err))
+		}
+	}
+
+	return s.issue(ctx, user, uuid.New().String())
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already used
+// means it has leaked, so the whole family is revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*model.AuthResponse, error) {
+	stored, err := s.tokens.FindRefreshToken(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		if err := s.tokens.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Only the request that actually revokes the token may rotate it; losing
+	// the race to a concurrent refresh with the same token is reuse too.
+	revoked, err := s.tokens.RevokeRefreshToken(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		if err := s.tokens.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.users.GetUserById(ctx, stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.issue(ctx, user, stored.FamilyID)
+}
+
+// Logout revokes the refresh token family and the access token used for the call.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string, accessJTI string, accessExpiry time.Time) error {
+	if refreshToken != "" {
+		stored, err := s.tokens.FindRefreshToken(ctx, auth.HashToken(refreshToken))
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			if err := s.tokens.RevokeFamily(ctx, stored.FamilyID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if accessJTI != "" {
+		if err := s.tokens.RevokeAccessToken(ctx, accessJTI, accessExpiry); err != nil {
+			return err
+		}
+	}
+
+	return s.tokens.DeleteExpired(ctx)
+}
+
+// recordFailure counts a failed password for user and locks the account once
+// the failure count reaches the lockout threshold.
+func (s *AuthService) recordFailure(ctx context.Context, user *model.User) {
+	log := logger.L(ctx)
+
+	failures, lockedUntil, err := s.users.RecordLoginFailure(ctx, user.ID, s.lockout.LockFor)
+	if err != nil {
+		log.Error("Failed to record login failure", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+	if lockedUntil != nil {
+		log.Warn("Account locked after failed logins",
+			zap.Uint("user_id", user.ID), zap.Int("failures", failures), zap.Time("locked_until", *lockedUntil))
+	}
+}
+
+// fakeHash is a hash of a random password, verified against when the email is
+// unknown or the account locked. It is computed on first use with the configured algorithm and cost.
+func (s *AuthService) fakeHash() string {
+	s.dummyHashOnce.Do(func() {
+		s.dummyHash, _ = s.hasher.Hash(uuid.New().String())
+	})
+	return s.dummyHash
+}
+
+func (s *AuthService) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.tokens.IsAccessTokenRevoked(ctx, jti)
+}
+
+func (s *AuthService) issue(ctx context.Context, user *model.User, familyID string) (*model.AuthResponse, error) {
+	// A user with TOTP on reaches this only through CompleteMFA, or by refreshing
+	// a session that either passed the challenge or proved a code to enroll.
+	amr := []string{"pwd"}
+	if user.TOTPEnabled {
+		amr = append(amr, "otp")
+	}
+
+	accessToken, err := s.keys.GenerateToken(user.ID, user.Email, user.Role, amr, s.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tokens.CreateRefreshToken(ctx, &model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
+}
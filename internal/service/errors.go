@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Services report failures a client can act on with the error types below,
+// or with a *validation.Error for input that breaks a rule. Controllers map
+// each type to one kind of problem response, so a new failure only needs the
+// right type to be reported correctly.
+
+// NotFoundError is returned when the resource being read or changed does not exist.
+type NotFoundError struct {
+	Resource string // e.g. "user"
+}
+
+func (e *NotFoundError) Error() string { return e.Resource + " not found" }
+
+// Is keeps errors.Is(err, gorm.ErrRecordNotFound) working for callers that
+// predate NotFoundError.
+func (e *NotFoundError) Is(target error) bool { return target == gorm.ErrRecordNotFound }
+
+// ConflictError is returned when a write clashes with the current state, such
+// as a unique field already being in use. Field names the clashing request
+// field, if there is a single one.
+type ConflictError struct {
+	Resource string
+	Field    string
+	Reason   string
+}
+
+func (e *ConflictError) Error() string { return e.Reason }
+
+// InvalidRequestError is returned when a request is well formed but refers to
+// something unusable, such as an expired link.
+type InvalidRequestError struct {
+	Reason string
+}
+
+func (e *InvalidRequestError) Error() string { return e.Reason }
+
+// UnauthorizedError is returned when the caller's credentials are missing,
+// wrong or no longer valid.
+type UnauthorizedError struct {
+	Reason string
+}
+
+func (e *UnauthorizedError) Error() string { return e.Reason }
+
+// ForbiddenError is returned when the caller is known but may not do this.
+type ForbiddenError struct {
+	Reason string
+}
+
+func (e *ForbiddenError) Error() string { return e.Reason }
+
+// ErrUserNotFound is returned for a user id that does not exist or is deleted.
+var ErrUserNotFound = &NotFoundError{Resource: "user"}
+
+// userError replaces the repository's errors for a user row with the
+// service's own: a missing row becomes ErrUserNotFound and a unique index
+// violation, which checkUnique lost a race to, a ConflictError.
+func userError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return &ConflictError{Resource: "user", Reason: "email or phone already in use"}
+	}
+	return err
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/internal/model"
+	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/validation"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RecoveryCodeCount is how many recovery codes are issued when TOTP is enabled.
+const RecoveryCodeCount = 10
+
+// ErrInvalidMFACode is returned for a wrong, reused or missing second factor.
+var ErrInvalidMFACode = &ForbiddenError{Reason: "invalid two-factor code"}
+
+// ErrMFAAlreadyEnabled is returned when enrolling a user who already has TOTP on.
+var ErrMFAAlreadyEnabled = &ConflictError{Resource: "two-factor authentication", Reason: "two-factor authentication already enabled"}
+
+// ErrMFANotEnrolled is returned when confirming or disabling TOTP that was never set up.
+var ErrMFANotEnrolled = &ConflictError{Resource: "two-factor authentication", Reason: "two-factor authentication not enrolled"}
+
+type MFAServiceInterface interface {
+	Enroll(ctx context.Context, userID uint, req model.TOTPEnrollRequest) (*model.TOTPEnrollmentResponse, error)
+	Confirm(ctx context.Context, userID uint, req model.MFACodeRequest) (*model.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID uint, req model.MFACodeRequest) error
+	Verify(ctx context.Context, user *model.User, code, recoveryCode string) error
+}
+
+type MFAService struct {
+	users  repository.UserRepoInterface
+	repo   repository.MFARepoInterface
+	hasher *auth.PasswordHasher
+	tx     repository.Transactor
+	audit  AuditServiceInterface
+	issuer string
+}
+
+func NewMFAService(users repository.UserRepoInterface, repo repository.MFARepoInterface, hasher *auth.PasswordHasher, tx repository.Transactor, audit AuditServiceInterface, issuer string) MFAServiceInterface {
+	return &MFAService{users: users, repo: repo, hasher: hasher, tx: tx, audit: audit, issuer: issuer}
+}
+
+// Enroll generates a new pending TOTP secret for the user once they confirm
+// their current password. Two-factor login is not enforced until Confirm
+// proves the authenticator app produces codes.
+func (s *MFAService) Enroll(ctx context.Context, userID uint, req model.TOTPEnrollRequest) (*model.TOTPEnrollmentResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, userError(err)
+	}
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	ok, _, err := s.hasher.Verify(user.Password, req.Password)
+	if errors.Is(err, auth.ErrUnknownHashFormat) {
+		ok, err = false, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidPassword
+	}
+
+	key, err := auth.NewTOTPKey(s.issuer, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetTOTPSecret(ctx, userID, key.Secret); err != nil {
+		return nil, err
+	}
+
+	return &model.TOTPEnrollmentResponse{Secret: key.Secret, OTPAuthURI: key.URI, QRCode: key.QRCode}, nil
+}
+
+// Confirm enables TOTP once the user submits a valid code for the pending
+// secret, and returns a fresh set of recovery codes.
+func (s *MFAService) Confirm(ctx context.Context, userID uint, req model.MFACodeRequest) (*model.RecoveryCodesResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, userError(err)
+	}
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := auth.MatchTOTP(user.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	after := *user
+	after.TOTPEnabled = true
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.EnableTOTP(ctx, userID, step, hashes); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditActionMFAEnable, userID, user, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns TOTP off after checking a current code.
+func (s *MFAService) Disable(ctx context.Context, userID uint, req model.MFACodeRequest) error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	user, err := s.users.GetUserById(ctx, userID)
+	if err != nil {
+		return userError(err)
+	}
+	if !user.TOTPEnabled {
+		return ErrMFANotEnrolled
+	}
+	if err := s.Verify(ctx, user, req.Code, ""); err != nil {
+		return err
+	}
+
+	after := *user
+	after.TOTPEnabled = false
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DisableTOTP(ctx, userID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditActionMFADisable, userID, user, &after)
+	})
+}
+
+// Verify checks a TOTP code, or failing that a recovery code, for a user with
+// TOTP enabled. Accepted codes are consumed.
+func (s *MFAService) Verify(ctx context.Context, user *model.User, code, recoveryCode string) error {
+	if !user.TOTPEnabled {
+		return ErrMFANotEnrolled
+	}
+
+	if code != "" {
+		step, ok := auth.MatchTOTP(user.TOTPSecret, code, time.Now())
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		fresh, err := s.repo.AdvanceTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	if recoveryCode != "" {
+		used, err := s.repo.UseRecoveryCode(ctx, user.ID, auth.HashRecoveryCode(recoveryCode))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		left, _ := s.repo.CountRecoveryCodes(ctx, user.ID)
+		logger.L(ctx).Warn("Recovery code used", zap.Uint("user_id", user.ID), zap.Int64("remaining", left))
+		return nil
+	}
+
+	return ErrInvalidMFACode
+}
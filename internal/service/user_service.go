@@ -2,58 +2,385 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"go-crud-oapi/internal/model"
 	"go-crud-oapi/internal/repository"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/validation"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or was
+// issued for a different sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrInvalidPassword is returned when a supplied current password does not match.
+var ErrInvalidPassword = &ForbiddenError{Reason: "current password is incorrect"}
+
+// ErrNotDeleted is returned when restoring a user that is not soft-deleted.
+var ErrNotDeleted = &ConflictError{Resource: "user", Reason: "user is not deleted"}
+
+// ErrVersionConflict is returned when the caller's expected version is stale.
+var ErrVersionConflict = repository.ErrVersionConflict
+
 type UserServiceInterFace interface {
 	Create(ctx context.Context, user *model.User) error
-	ListAllUsers(ctx context.Context) ([]model.User, error)
+	ListUsers(ctx context.Context, params model.UserListParams, cursor string) (*model.UserPage, error)
 	Get(ctx context.Context, id uint) (*model.User, error)
+	GetIncludingDeleted(ctx context.Context, id uint) (*model.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
 	Update(ctx context.Context, id uint, user *model.User) error
-	Delete(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint, version uint) error
+	Restore(ctx context.Context, id uint) error
+	Unlock(ctx context.Context, id uint) error
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int, error)
+	ChangePassword(ctx context.Context, id uint, req model.ChangePasswordRequest) error
 }
 
+// UserService writes every mutation and its audit entry in one transaction,
+// so a committed change always has its entry.
 type UserService struct {
-	repo repository.UserRepoInterface
+	repo   repository.UserRepoInterface
+	tx     repository.Transactor
+	hasher *auth.PasswordHasher
+	audit  AuditServiceInterface
 }
 
-func NewUserService(repo repository.UserRepoInterface) UserServiceInterFace {
-	return &UserService{repo: repo}
+func NewUserService(repo repository.UserRepoInterface, tx repository.Transactor, hasher *auth.PasswordHasher, audit AuditServiceInterface) UserServiceInterFace {
+	return &UserService{repo: repo, tx: tx, hasher: hasher, audit: audit}
 }
 
 func (s *UserService) Create(ctx context.Context, user *model.User) error {
-	return s.repo.Create(ctx, user)
+	if err := validation.Struct(user); err != nil {
+		return err
+	}
+	if err := s.checkUnique(ctx, 0, user); err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	user.Version = 1
+
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, user); err != nil {
+			return userError(err)
+		}
+		return s.audit.Record(ctx, model.AuditActionCreate, user.ID, nil, user)
+	})
 }
 
-func (s *UserService) ListAllUsers(ctx context.Context) ([]model.User, error) {
-	return s.repo.ListAllUsers(ctx)
+// ListUsers returns one page of users. cursor is the opaque NextCursor of the
+// previous page and must have been issued for the same sort order.
+func (s *UserService) ListUsers(ctx context.Context, params model.UserListParams, cursor string) (*model.UserPage, error) {
+	if params.Limit <= 0 {
+		params.Limit = DefaultPageSize
+	}
+	if params.Limit > MaxPageSize {
+		params.Limit = MaxPageSize
+	}
+	if params.SortBy == "" {
+		params.SortBy = "id"
+	}
+
+	if cursor != "" {
+		after, err := decodeCursor(cursor, sortKey(params))
+		if err != nil {
+			return nil, err
+		}
+		params.After = after
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	requested := params.Limit
+	params.Limit++
+	users, err := s.repo.ListUsers(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.UserPage{Items: users}
+	if len(users) > requested {
+		page.Items = users[:requested]
+		page.NextCursor = encodeCursor(params, page.Items[requested-1])
+	}
+	return page, nil
 }
 
 func (s *UserService) Get(ctx context.Context, id uint) (*model.User, error) {
-	return s.repo.GetUserById(ctx, id)
+	user, err := s.repo.GetUserById(ctx, id)
+	if err != nil {
+		return nil, userError(err)
+	}
+	return user, nil
+}
+
+func (s *UserService) GetIncludingDeleted(ctx context.Context, id uint) (*model.User, error) {
+	user, err := s.repo.GetUserByIdUnscoped(ctx, id)
+	if err != nil {
+		return nil, userError(err)
+	}
+	return user, nil
 }
 
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	return s.repo.FindByEmail(ctx, email)
 }
 
+// Update validates user and saves it as the complete new state of user id,
+// then refreshes user from the database. An empty password leaves the stored
+// one unchanged. A non-zero user.Version is the version the caller last saw;
+// ErrVersionConflict is returned if it is no longer current. Another user
+// already having the email or phone is a ConflictError naming that field.
 func (s *UserService) Update(ctx context.Context, id uint, user *model.User) error {
+	var err error
+	if user.Password == "" {
+		err = validation.Struct(user, "Password")
+	} else {
+		err = validation.Struct(user)
+	}
+	if err != nil {
+		return err
+	}
+
+	before, err := s.repo.GetUserById(ctx, id)
+	if err != nil {
+		return userError(err)
+	}
+	if user.Version != 0 && user.Version != before.Version {
+		return ErrVersionConflict
+	}
+	if err := s.checkUnique(ctx, id, user); err != nil {
+		return err
+	}
+
+	if user.Password != "" {
+		hash, err := s.hasher.Hash(user.Password)
+		if err != nil {
+			return err
+		}
+		user.Password = hash
+	}
+
+	// A new email address has to be verified again.
+	user.EmailVerifiedAt = nil
+	if user.Email == before.Email {
+		user.EmailVerifiedAt = before.EmailVerifiedAt
+	}
+
 	user.ID = id
-	return s.repo.UpdateUser(ctx, id, user)
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateUser(ctx, id, user); err != nil {
+			return userError(err)
+		}
+
+		after, err := s.repo.GetUserById(ctx, id)
+		if err != nil {
+			return userError(err)
+		}
+		if err := s.audit.Record(ctx, model.AuditActionUpdate, id, before, after); err != nil {
+			return err
+		}
+
+		*user = *after
+		return nil
+	})
+}
+
+// ChangePassword replaces the password of user id after checking the current one.
+func (s *UserService) ChangePassword(ctx context.Context, id uint, req model.ChangePasswordRequest) error {
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetUserById(ctx, id)
+	if err != nil {
+		return userError(err)
+	}
+
+	ok, _, err := s.hasher.Verify(user.Password, req.OldPassword)
+	if errors.Is(err, auth.ErrUnknownHashFormat) {
+		ok, err = false, nil
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidPassword
+	}
+
+	hash, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	after := *user
+	after.Password = hash
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdatePassword(ctx, id, hash); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditActionPasswordChange, id, user, &after)
+	})
+}
+
+// Delete soft-deletes user id. A non-zero version must be the current one.
+func (s *UserService) Delete(ctx context.Context, id uint, version uint) error {
+	user, err := s.repo.GetUserById(ctx, id)
+	if err != nil {
+		return userError(err)
+	}
+	if version != 0 && version != user.Version {
+		return ErrVersionConflict
+	}
+
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteUser(ctx, id, version); err != nil {
+			return userError(err)
+		}
+		return s.audit.Record(ctx, model.AuditActionDelete, id, user, nil)
+	})
+}
+
+// Restore undoes a soft delete. It fails with a ConflictError if a user
+// created since has taken the email or phone.
+func (s *UserService) Restore(ctx context.Context, id uint) error {
+	before, err := s.repo.GetUserByIdUnscoped(ctx, id)
+	if err != nil {
+		return userError(err)
+	}
+	if !before.DeletedAt.Valid {
+		return ErrNotDeleted
+	}
+	if err := s.checkUnique(ctx, id, before); err != nil {
+		return err
+	}
+
+	after := *before
+	after.DeletedAt = gorm.DeletedAt{}
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreUser(ctx, id); err != nil {
+			return userError(err)
+		}
+		return s.audit.Record(ctx, model.AuditActionRestore, id, before, &after)
+	})
 }
 
-func (s *UserService) Delete(ctx context.Context, id uint) error {
+// Unlock clears the failed login count and any lockout on user id.
+func (s *UserService) Unlock(ctx context.Context, id uint) error {
 	user, err := s.repo.GetUserById(ctx, id)
+	if err != nil {
+		return userError(err)
+	}
+
+	after := *user
+	after.FailedLogins = 0
+	after.LockedUntil = nil
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.ResetLoginFailures(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, model.AuditActionUnlock, id, user, &after)
+	})
+}
+
+// PurgeDeleted permanently removes users that were soft-deleted more than
+// retention ago and returns how many were removed.
+func (s *UserService) PurgeDeleted(ctx context.Context, retention time.Duration) (int, error) {
+	var purged []model.User
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		purged, err = s.repo.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		for i := range purged {
+			if err := s.audit.Record(ctx, model.AuditActionPurge, purged[i].ID, &purged[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(purged), nil
+}
+
+// checkUnique returns a ConflictError naming the field if a user other than id
+// already has user's email or phone.
+func (s *UserService) checkUnique(ctx context.Context, id uint, user *model.User) error {
+	existing, err := s.repo.FindByEmail(ctx, user.Email)
 	if err != nil {
 		return err
 	}
-	if user == nil {
-		return gorm.ErrRecordNotFound
+	if existing != nil && existing.ID != id {
+		return &ConflictError{Resource: "user", Field: "email", Reason: "email already in use"}
 	}
 
-	return s.repo.DeleteUser(ctx, id)
+	existing, err = s.repo.FindByPhone(ctx, user.Phone)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return &ConflictError{Resource: "user", Field: "phone", Reason: "phone already in use"}
+	}
+	return nil
+}
+
+func sortKey(params model.UserListParams) string {
+	if params.SortDesc {
+		return "-" + params.SortBy
+	}
+	return params.SortBy
+}
+
+func encodeCursor(params model.UserListParams, last model.User) string {
+	c := model.UserCursor{Sort: sortKey(params), ID: last.ID}
+	switch params.SortBy {
+	case "name":
+		c.Value = last.Name
+	case "email":
+		c.Value = last.Email
+	case "age":
+		c.Value = last.Age
+	}
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor string, sort string) (*model.UserCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c model.UserCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+
+	// JSON numbers decode as float64; keep the value typed like the column.
+	switch v := c.Value.(type) {
+	case float64:
+		c.Value = int(v)
+	case string:
+	case nil:
+		if sort != "id" && sort != "-id" {
+			return nil, ErrInvalidCursor
+		}
+	default:
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
 }
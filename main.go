@@ -1,19 +1,20 @@
 package main
 
 import (
+	"context"
 	"go-crud-oapi/config"
-	"go-crud-oapi/internal/controller"
+	"go-crud-oapi/internal/app"
 	"go-crud-oapi/internal/db"
+	"go-crud-oapi/internal/jobs"
 	"go-crud-oapi/internal/model"
-	"go-crud-oapi/internal/repository"
-	"go-crud-oapi/internal/router"
-	"go-crud-oapi/internal/service"
+	"go-crud-oapi/pkg/auth"
 	"go-crud-oapi/pkg/logger"
+	"go-crud-oapi/pkg/mailer"
 	"log"
 	"net/http"
+	"os"
 
 	_ "github.com/lib/pq"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -21,16 +22,37 @@ func main() {
 	logger.Init()
 
 	cfg := config.Load()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(cfg, os.Args[2:])
+		return
+	}
+
 	dbConn := db.Init(cfg)
-	seedAdminUser(dbConn)
 
-	repo := repository.NewUserRepository(dbConn)
-	svc := service.NewUserService(repo)
-	userController := controller.NewUserController(svc)
-	authController := controller.NewAuthController(repo)
+	mail, err := mailer.New(mailer.Config{
+		Driver:   cfg.Mailer,
+		From:     cfg.MailFrom,
+		SMTPHost: cfg.SMTPHost,
+		SMTPPort: cfg.SMTPPort,
+		SMTPUser: cfg.SMTPUser,
+		SMTPPass: cfg.SMTPPassword,
+		Dir:      cfg.MailDir,
+	})
+	if err != nil {
+		log.Fatalf("❌ Invalid mailer config: %v", err)
+	}
+
+	a, err := app.New(cfg, dbConn, mail)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if cfg.OpenAPIValidateResponses {
+		log.Println("🔎 Validating responses against the OpenAPI spec")
+	}
+	seedAdminUser(dbConn, a.Hasher)
 
-	// Inject all controllers to router
-	r := router.NewRouter(userController, authController)
+	jobs.StartUserPurge(context.Background(), a.Users, cfg.SoftDeleteRetention, cfg.PurgeInterval)
+	jobs.StartIdempotencyCleanup(context.Background(), a.Idempotency, cfg.PurgeInterval)
 
 	port := cfg.ServerPort
 	//port := os.Getenv("PORT")
@@ -39,15 +61,15 @@ func main() {
 	}
 
 	log.Printf("🚀 Server running on : %s", port)
-	http.ListenAndServe(":"+port, r)
+	http.ListenAndServe(":"+port, a.Handler)
 
 }
 
-func seedAdminUser(db *gorm.DB) {
+func seedAdminUser(db *gorm.DB, hasher *auth.PasswordHasher) {
 	var count int64
 	db.Model(&model.User{}).Where("email = ?", "admin@example.com").Count(&count)
 	if count == 0 {
-		hash, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
+		hash, _ := hasher.Hash("admin123")
 		db.Create(&model.User{
 			Name:     "Admin User",
 			Email:    "admin@example.com",
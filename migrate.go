@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-crud-oapi/config"
+	"go-crud-oapi/internal/db"
+	"log"
+	"os"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+// runMigrate implements the `migrate up`, `migrate down [steps]` and
+// `migrate status` subcommands.
+func runMigrate(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	gormDB, driver := db.Connect(cfg)
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		log.Fatal("❌ Failed to get sql.DB from GORM:", err)
+	}
+	defer sqlDB.Close()
+
+	migrator, err := db.NewMigrator(sqlDB, driver)
+	if err != nil {
+		log.Fatal("❌ Failed to load migrations:", err)
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatal("❌ Migration failed:", err)
+		}
+		log.Printf("✅ %d migration(s) applied", applied)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatal(migrateUsage)
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatal("❌ Rollback failed:", err)
+		}
+		log.Printf("✅ %d migration(s) rolled back", rolledBack)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("❌ Failed to read migration status:", err)
+		}
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(os.Stdout, "%04d  %-30s  %s\n", st.Version, st.Name, applied)
+		}
+		if err := migrator.CheckCompatible(ctx); err != nil {
+			log.Printf("⚠️  %v", err)
+		}
+
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyConfig selects how access tokens are signed.
+type KeyConfig struct {
+	Algorithm      string   // HS256, RS256 or EdDSA
+	Secret         string   // HS256 shared secret
+	SigningKeyFile string   // PEM private key for RS256/EdDSA
+	VerifyKeyFiles []string // PEM public (or private) keys of retired signing keys, still accepted
+	Issuer         string   // iss of every token, and aud of the tokens only this service accepts
+	Audience       string   // aud of access tokens, which other services may accept too
+}
+
+// KeyManager signs access tokens with one current key and verifies them
+// against every key it knows, looked up by the token's kid header. Rotating
+// keys means moving the old signing key to VerifyKeyFiles until the tokens
+// it signed have expired.
+//
+// Every token names the service as its issuer. Access tokens are addressed to
+// the configured audience; every other kind, such as MFA challenges and reset
+// links, to the issuer itself, so that another service verifying tokens
+// against the JWKS rejects them by audience alone.
+type KeyManager struct {
+	signing  *jwtKey
+	keys     map[string]*jwtKey
+	ordered  []*jwtKey // signing key first, then verification keys as configured
+	issuer   string
+	audience string
+}
+
+type jwtKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private any // signing material; nil for verification-only keys
+	public  any // verification material
+}
+
+// JWK is a public key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeyManager loads the keys described by cfg.
+func NewKeyManager(cfg KeyConfig) (*KeyManager, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("tokens need an issuer and an audience")
+	}
+	if cfg.Issuer == cfg.Audience {
+		return nil, errors.New("the access token audience must differ from the issuer")
+	}
+	km := &KeyManager{keys: make(map[string]*jwtKey), issuer: cfg.Issuer, audience: cfg.Audience}
+
+	switch cfg.Algorithm {
+	case "", "HS256":
+		if cfg.Secret == "" {
+			return nil, errors.New("HS256 requires a secret")
+		}
+		sum := sha256.Sum256([]byte(cfg.Secret))
+		km.signing = &jwtKey{
+			kid:     "hs-" + base64.RawURLEncoding.EncodeToString(sum[:8]),
+			method:  jwt.SigningMethodHS256,
+			private: []byte(cfg.Secret),
+			public:  []byte(cfg.Secret),
+		}
+	case "RS256", "EdDSA":
+		if cfg.SigningKeyFile == "" {
+			return nil, fmt.Errorf("%s requires a signing key file", cfg.Algorithm)
+		}
+		key, err := loadKeyFile(cfg.SigningKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key.private == nil {
+			return nil, fmt.Errorf("%s: not a private key", cfg.SigningKeyFile)
+		}
+		if key.method.Alg() != cfg.Algorithm {
+			return nil, fmt.Errorf("%s: key is for %s, not %s", cfg.SigningKeyFile, key.method.Alg(), cfg.Algorithm)
+		}
+		km.signing = key
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+	km.keys[km.signing.kid] = km.signing
+	km.ordered = append(km.ordered, km.signing)
+
+	for _, path := range cfg.VerifyKeyFiles {
+		key, err := loadKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key.private = nil
+		if _, ok := km.keys[key.kid]; !ok {
+			km.keys[key.kid] = key
+			km.ordered = append(km.ordered, key)
+		}
+	}
+
+	return km, nil
+}
+
+// Sign signs claims with the current key and names it in the kid header.
+func (km *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(km.signing.method, claims)
+	token.Header["kid"] = km.signing.kid
+	return token.SignedString(km.signing.private)
+}
+
+// Parse verifies tokenStr against the key named by its kid header and checks
+// that the token's alg matches that key, that the service issued it and that
+// it is addressed to audience.
+func (km *KeyManager) Parse(tokenStr, audience string) (*jwt.Token, error) {
+	return jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := km.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if t.Method.Alg() != key.method.Alg() {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return key.public, nil
+	}, jwt.WithIssuer(km.issuer), jwt.WithAudience(audience))
+}
+
+// ParseAccessToken is Parse for access tokens.
+func (km *KeyManager) ParseAccessToken(tokenStr string) (*jwt.Token, error) {
+	return km.Parse(tokenStr, km.audience)
+}
+
+// JWKS returns the public keys that verify tokens. Shared HS256 secrets are
+// never published.
+func (km *KeyManager) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range km.ordered {
+		if jwk, ok := toJWK(key); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+func toJWK(key *jwtKey) (JWK, bool) {
+	switch pub := key.public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: key.kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: key.kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+// loadKeyFile reads an RSA or Ed25519 key from a PEM file. Private keys may be
+// PKCS#8 or PKCS#1, public keys PKIX.
+func loadKeyFile(path string) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block found", path)
+	}
+
+	key := &jwtKey{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		signer, ok := priv.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%s: only RSA and Ed25519 keys are supported", path)
+		}
+		key.private = priv
+		key.public = signer.Public()
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		key.private = priv
+		key.public = &priv.PublicKey
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		key.public = pub
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+
+	switch key.public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("%s: only RSA and Ed25519 keys are supported", path)
+	}
+
+	jwk, _ := toJWK(key)
+	key.kid = thumbprint(jwk)
+	return key, nil
+}
+
+// thumbprint is the RFC 7638 JWK thumbprint, used as the kid so that it is
+// stable for a key no matter which file it was loaded from.
+func thumbprint(jwk JWK) string {
+	var members any
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+	raw, _ := json.Marshal(members)
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
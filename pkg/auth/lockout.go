@@ -0,0 +1,27 @@
+package auth
+
+import "time"
+
+// Backoff computes how long to block further login attempts after repeated
+// failures. Below Threshold failures nothing is blocked; from then on the
+// block starts at Base and doubles with every further failure, up to Max.
+type Backoff struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+}
+
+// LockFor returns how long to block after the given number of consecutive failures.
+func (b Backoff) LockFor(failures int) time.Duration {
+	if b.Threshold <= 0 || failures < b.Threshold {
+		return 0
+	}
+	d := b.Base
+	for i := b.Threshold; i < failures && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
@@ -0,0 +1,43 @@
+package auth
+
+// Permission names one action a caller may perform. Routes require
+// permissions rather than roles so that the role matrix lives in one place.
+type Permission string
+
+const (
+	PermUsersRead        Permission = "users:read"
+	PermUsersReadPrivate Permission = "users:read_private" // email, phone and age of any user
+	PermUsersWrite       Permission = "users:write"
+	PermUsersDelete      Permission = "users:delete"
+	PermUsersRestore     Permission = "users:restore"
+	PermAuditRead        Permission = "audit:read"
+	PermTokensManage     Permission = "tokens:manage" // API tokens of other users and service accounts
+)
+
+var rolePermissions = map[string][]Permission{
+	"admin": {
+		PermUsersRead,
+		PermUsersReadPrivate,
+		PermUsersWrite,
+		PermUsersDelete,
+		PermUsersRestore,
+		PermAuditRead,
+		PermTokensManage,
+	},
+	"user": {
+		PermUsersRead,
+	},
+	"viewer": {
+		PermUsersRead,
+	},
+}
+
+// HasPermission reports whether role is granted perm.
+func HasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -1,22 +1,138 @@
 package auth
 
 import (
-	"os"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+// Values of the token_use claim, which keeps every other kind of token from
+// being accepted as an access token, and each from standing in for another.
+const (
+	TokenUseAccess        = "access"
+	TokenUseMFA           = "mfa"
+	TokenUsePasswordReset = "password_reset"
+	TokenUseVerifyEmail   = "verify_email"
+)
+
+// ErrWrongTokenUse is returned when a token is presented for the wrong purpose.
+var ErrWrongTokenUse = errors.New("token presented for the wrong purpose")
+
+// GenerateToken signs a short-lived access token whose subject is the user id,
+// addressed to the access token audience. Every token carries a unique jti so
+// it can be revoked before it expires.
+// amr lists the RFC 8176 authentication methods used, e.g. "pwd" and "otp".
+func (km *KeyManager) GenerateToken(userID uint, email, role string, amr []string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"jti":       uuid.New().String(),
+		"sub":       strconv.FormatUint(uint64(userID), 10),
+		"email":     email,
+		"role":      role,
+		"amr":       amr,
+		"token_use": TokenUseAccess,
+		"iss":       km.issuer,
+		"aud":       km.audience,
+		"exp":       now.Add(ttl).Unix(),
+		"iat":       now.Unix(),
+	}
+
+	return km.Sign(claims)
+}
 
-func GenerateToken(email, role string) (string, error) {
+// GenerateMFAToken signs the short-lived challenge token returned by the
+// first login step. It only proves the password was right.
+func (km *KeyManager) GenerateMFAToken(userID uint, ttl time.Duration) (string, error) {
+	return km.GenerateActionToken(userID, TokenUseMFA, "", ttl)
+}
+
+// ParseMFAToken verifies an MFA challenge token and returns its user id.
+func (km *KeyManager) ParseMFAToken(tokenStr string) (uint, error) {
+	id, _, err := km.ParseActionToken(tokenStr, TokenUseMFA)
+	return id, err
+}
+
+// GenerateActionToken signs a token that lets its bearer perform one kind of
+// action (use) on behalf of a user, such as resetting their password. binding
+// is a value derived from the user's state that the action will change, so
+// comparing it on use makes the token single-use without storing it. The
+// token is addressed to the issuer, so it is never taken for an access token.
+func (km *KeyManager) GenerateActionToken(userID uint, use, binding string, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"email": email,
-		"role":  role,
-		"exp":   time.Now().Add(24 * time.Hour).Unix(), // Token valid for 1 day
-		"iat":   time.Now().Unix(),
+		"jti":       uuid.New().String(),
+		"sub":       strconv.FormatUint(uint64(userID), 10),
+		"token_use": use,
+		"iss":       km.issuer,
+		"aud":       km.issuer,
+		"exp":       now.Add(ttl).Unix(),
+		"iat":       now.Unix(),
 	}
+	if binding != "" {
+		claims["bnd"] = binding
+	}
+
+	return km.Sign(claims)
+}
+
+// ParseActionToken verifies a token made by GenerateActionToken for use and
+// returns its user id and binding.
+func (km *KeyManager) ParseActionToken(tokenStr, use string) (uint, string, error) {
+	token, err := km.Parse(tokenStr, km.issuer)
+	if err != nil {
+		return 0, "", err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, "", jwt.ErrTokenInvalidClaims
+	}
+	if got, _ := claims["token_use"].(string); got != use {
+		return 0, "", ErrWrongTokenUse
+	}
+	sub, _ := claims["sub"].(string)
+	id, err := strconv.ParseUint(sub, 10, 0)
+	if err != nil {
+		return 0, "", jwt.ErrTokenInvalidClaims
+	}
+	binding, _ := claims["bnd"].(string)
+	return uint(id), binding, nil
+}
+
+// GenerateRefreshToken returns an opaque random refresh token and the hash
+// that should be persisted in its place.
+func GenerateRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// APITokenPrefix starts every personal access token, which lets the auth
+// middleware tell them from JWTs and makes leaked tokens easy to scan for.
+const APITokenPrefix = "pat_"
+
+// GenerateAPIToken returns a new personal access token, the short prefix shown
+// when listing it, and the hash to store.
+func GenerateAPIToken() (token, prefix, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	token = APITokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, token[:len(APITokenPrefix)+8], HashToken(token), nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+// HashToken returns the hex encoded SHA-256 of an opaque token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
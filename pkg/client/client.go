@@ -0,0 +1,389 @@
+// Package client is a Go client for the user service. The types and RawClient
+// in client.gen.go are generated from api/openapi.yaml; Client wraps them with
+// typed methods for the common calls, pagination iterators, bearer token
+// handling with automatic refresh, and retries of idempotent requests.
+//
+//	c, err := client.New("https://users.internal")
+//	if _, err := c.Login(ctx, email, password); err != nil { ... }
+//	for user, err := range c.Users(ctx, nil) { ... }
+//
+// Operations without a typed method are available through Raw, which shares
+// the same tokens and retry policy.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Version is the version of api/openapi.yaml this client was generated from.
+const Version = "1.0.0"
+
+// UserAgent is sent with every request.
+const UserAgent = "go-crud-oapi-client/" + Version
+
+// Client calls the user service as one user or service account.
+type Client struct {
+	raw    *ClientWithResponses
+	tokens *tokenStore
+}
+
+type options struct {
+	doer       HttpRequestDoer
+	access     string
+	refresh    string
+	onChange   func(access, refresh string)
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Option configures a Client.
+type Option func(*options)
+
+// WithHTTPDoer sends requests through doer instead of http.DefaultClient.
+func WithHTTPDoer(doer HttpRequestDoer) Option {
+	return func(o *options) { o.doer = doer }
+}
+
+// WithTokens starts the client with tokens from an earlier Login. The refresh
+// token may be empty, in which case an expired access token is not renewed.
+// Personal access tokens ("pat_...") are passed as the access token.
+func WithTokens(access, refresh string) Option {
+	return func(o *options) { o.access, o.refresh = access, refresh }
+}
+
+// WithTokenCallback calls fn whenever the client obtains new tokens, so that
+// they can be persisted. fn must not call back into the Client.
+func WithTokenCallback(fn func(access, refresh string)) Option {
+	return func(o *options) { o.onChange = fn }
+}
+
+// WithRetries retries idempotent requests up to max times, waiting backoff
+// before the first retry and doubling it each time. The default is 3 retries
+// starting at 200ms; max 0 disables retries.
+func WithRetries(max int, backoff time.Duration) Option {
+	return func(o *options) { o.maxRetries, o.backoff = max, backoff }
+}
+
+// New returns a Client for the service at server, e.g. "http://localhost:8080".
+func New(server string, opts ...Option) (*Client, error) {
+	o := options{doer: http.DefaultClient, maxRetries: 3, backoff: 200 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Client{tokens: &tokenStore{access: o.access, refresh: o.refresh, onChange: o.onChange}}
+	t := &transport{
+		doer:       o.doer,
+		tokens:     c.tokens,
+		refresh:    c.refresh,
+		maxRetries: o.maxRetries,
+		backoff:    o.backoff,
+	}
+
+	raw, err := NewClientWithResponses(server, WithHTTPClient(t))
+	if err != nil {
+		return nil, err
+	}
+	c.raw = raw
+	return c, nil
+}
+
+// Raw exposes every operation of the API. Its requests carry the client's
+// tokens and are retried like those of the typed methods.
+func (c *Client) Raw() *ClientWithResponses {
+	return c.raw
+}
+
+// Tokens returns the current access and refresh tokens.
+func (c *Client) Tokens() (access, refresh string) {
+	return c.tokens.get()
+}
+
+// Login signs in with an email and password. When the account has two-factor
+// authentication enabled it returns the challenge to complete with VerifyMFA;
+// otherwise the client keeps the issued tokens and the challenge is nil.
+func (c *Client) Login(ctx context.Context, email, password string) (*MFAChallenge, error) {
+	resp, err := c.raw.LoginWithResponse(ctx, LoginRequest{Email: openapi_types.Email(email), Password: password})
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.Body)
+	}
+
+	challenge, err := resp.JSON200.AsMFAChallenge()
+	if err == nil && challenge.MfaRequired {
+		return &challenge, nil
+	}
+	tokens, err := resp.JSON200.AsAuthTokens()
+	if err != nil {
+		return nil, err
+	}
+	c.tokens.set(tokens.Token, tokens.RefreshToken)
+	return nil, nil
+}
+
+// VerifyMFA completes a Login challenge with a TOTP code, or with a recovery
+// code when req.RecoveryCode is set, and keeps the issued tokens.
+func (c *Client) VerifyMFA(ctx context.Context, req VerifyMFARequest) error {
+	resp, err := c.raw.VerifyMFAWithResponse(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	c.tokens.set(resp.JSON200.Token, resp.JSON200.RefreshToken)
+	return nil
+}
+
+// Refresh exchanges the refresh token for new tokens. Requests refresh
+// automatically when the access token has expired, so this is rarely needed.
+func (c *Client) Refresh(ctx context.Context) error {
+	_, refresh := c.tokens.get()
+	return c.refresh(ctx, refresh)
+}
+
+func (c *Client) refresh(ctx context.Context, refreshToken string) error {
+	resp, err := c.raw.RefreshTokenWithResponse(ctx, RefreshRequest{RefreshToken: &refreshToken})
+	if err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	c.tokens.set(resp.JSON200.Token, resp.JSON200.RefreshToken)
+	return nil
+}
+
+// Logout revokes the access token and the refresh token chain and forgets
+// both.
+func (c *Client) Logout(ctx context.Context) error {
+	_, refresh := c.tokens.get()
+	var req RefreshRequest
+	if refresh != "" {
+		req.RefreshToken = &refresh
+	}
+
+	resp, err := c.raw.LogoutWithResponse(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	c.tokens.set("", "")
+	return nil
+}
+
+// Me returns the authenticated user.
+func (c *Client) Me(ctx context.Context) (*UserFull, error) {
+	resp, err := c.raw.GetMeWithResponse(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// ListUsers returns one page of users. Use Users to iterate over all pages.
+func (c *Client) ListUsers(ctx context.Context, params *ListUsersParams) (*UserPage, error) {
+	resp, err := c.raw.ListUsersWithResponse(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// Users yields every user matching params, fetching further pages as the
+// loop advances. Iteration stops after the first error.
+func (c *Client) Users(ctx context.Context, params *ListUsersParams) iter.Seq2[UserView, error] {
+	return func(yield func(UserView, error) bool) {
+		var query ListUsersParams
+		if params != nil {
+			query = *params
+		}
+		for {
+			page, err := c.ListUsers(ctx, &query)
+			if err != nil {
+				yield(UserView{}, err)
+				return
+			}
+			for _, user := range page.Items {
+				if !yield(user, nil) {
+					return
+				}
+			}
+			if page.NextCursor == nil {
+				return
+			}
+			query.Cursor = page.NextCursor
+		}
+	}
+}
+
+// GetUser returns the user with the given id, filtered to what the caller may
+// see, and its ETag for use as ifMatch in UpdateUser, PatchUser or DeleteUser.
+func (c *Client) GetUser(ctx context.Context, id int) (*UserView, string, error) {
+	resp, err := c.raw.GetUserWithResponse(ctx, id, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.JSON200 == nil {
+		return nil, "", newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, resp.HTTPResponse.Header.Get("ETag"), nil
+}
+
+// CreateUser creates a user. Each call is sent with a new Idempotency-Key, so
+// it is retried safely and never creates the user twice.
+func (c *Client) CreateUser(ctx context.Context, req CreateUserRequest) (*UserFull, error) {
+	key := uuid.NewString()
+	resp, err := c.raw.CreateUserWithResponse(ctx, &CreateUserParams{IdempotencyKey: &key}, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON201 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON201, nil
+}
+
+// UpdateUser replaces the user's fields. When ifMatch is not empty the update
+// only succeeds if the user still has that ETag.
+func (c *Client) UpdateUser(ctx context.Context, id int, req UpdateUserRequest, ifMatch string) (*UserFull, error) {
+	resp, err := c.raw.UpdateUserWithResponse(ctx, id, &UpdateUserParams{IfMatch: optional(ifMatch)}, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// PatchUser applies a JSON merge patch to the user. When ifMatch is not empty
+// the patch only succeeds if the user still has that ETag.
+func (c *Client) PatchUser(ctx context.Context, id int, patch UserMergePatch, ifMatch string) (*UserFull, error) {
+	resp, err := c.raw.PatchUserWithApplicationMergePatchPlusJSONBodyWithResponse(ctx, id, &PatchUserParams{IfMatch: optional(ifMatch)}, patch)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// DeleteUser soft-deletes the user. When ifMatch is not empty the delete only
+// succeeds if the user still has that ETag.
+func (c *Client) DeleteUser(ctx context.Context, id int, ifMatch string) error {
+	resp, err := c.raw.DeleteUserWithResponse(ctx, id, &DeleteUserParams{IfMatch: optional(ifMatch)})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusNoContent {
+		return newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return nil
+}
+
+// RestoreUser undoes DeleteUser before the user is purged.
+func (c *Client) RestoreUser(ctx context.Context, id int) (*UserFull, error) {
+	resp, err := c.raw.RestoreUserWithResponse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, newAPIError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// AuditLogs yields every audit entry matching params, newest first, fetching
+// further pages as the loop advances. Iteration stops after the first error.
+func (c *Client) AuditLogs(ctx context.Context, params *ListAuditLogsParams) iter.Seq2[AuditLog, error] {
+	return func(yield func(AuditLog, error) bool) {
+		var query ListAuditLogsParams
+		if params != nil {
+			query = *params
+		}
+		for {
+			resp, err := c.raw.ListAuditLogsWithResponse(ctx, &query)
+			if err == nil && resp.JSON200 == nil {
+				err = newAPIError(resp.HTTPResponse, resp.Body)
+			}
+			if err != nil {
+				yield(AuditLog{}, err)
+				return
+			}
+			for _, entry := range resp.JSON200.Items {
+				if !yield(entry, nil) {
+					return
+				}
+			}
+			if resp.JSON200.NextCursor == nil {
+				return
+			}
+			query.Cursor = resp.JSON200.NextCursor
+		}
+	}
+}
+
+// APIError is returned for any response with an unexpected status. Problem
+// is decoded from the application/problem+json body; for any other body, as
+// from a proxy in front of the service, Detail holds the body text.
+type APIError struct {
+	StatusCode int
+	Problem
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("user service: %d %s", e.StatusCode, e.Title)
+	if e.Detail != nil {
+		msg += ": " + *e.Detail
+	}
+	if e.Errors != nil {
+		fields := make([]string, 0, len(*e.Errors))
+		for _, f := range *e.Errors {
+			fields = append(fields, f.Field+" "+f.Message)
+		}
+		msg += ": " + strings.Join(fields, "; ")
+	}
+	return msg
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{StatusCode: resp.StatusCode}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "application/problem+json" && json.Unmarshal(body, &e.Problem) == nil {
+		return e
+	}
+	e.Type = "about:blank"
+	e.Title = http.StatusText(resp.StatusCode)
+	e.Status = resp.StatusCode
+	e.Detail = optional(strings.TrimSpace(string(body)))
+	return e
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
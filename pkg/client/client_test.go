@@ -0,0 +1,482 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"go-crud-oapi/internal/apptest"
+	"go-crud-oapi/pkg/auth"
+	"go-crud-oapi/pkg/client"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	adminEmail    = "admin@example.com"
+	adminPassword = "Admin-Passw0rd"
+)
+
+func newServer(t *testing.T) *apptest.Server {
+	s := apptest.New(t)
+	s.CreateUser(t, "admin", adminEmail, adminPassword)
+	return s
+}
+
+// recorder is an HttpRequestDoer that notes every request the client sends
+// before passing it on.
+type recorder struct {
+	next client.HttpRequestDoer
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (r *recorder) Do(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	r.requests = append(r.requests, req.Method+" "+req.URL.Path)
+	r.mu.Unlock()
+	return r.next.Do(req)
+}
+
+// count returns how many requests were sent as "METHOD /path".
+func (r *recorder) count(request string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, req := range r.requests {
+		if req == request {
+			n++
+		}
+	}
+	return n
+}
+
+// flaky serves s behind a proxy that fails the first len(statuses) requests
+// for path with those statuses, sending Retry-After if it is set. For a zero
+// status the request does reach s, but its response is lost and replaced by
+// a 503. The proxy records the Idempotency-Key of each request for path.
+type flaky struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	attempts int
+	keys     []string
+}
+
+func newFlaky(t *testing.T, s *apptest.Server, path, retryAfter string, statuses ...int) *flaky {
+	f := &flaky{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			s.Handler.ServeHTTP(w, r)
+			return
+		}
+
+		f.mu.Lock()
+		f.attempts++
+		n := f.attempts
+		f.keys = append(f.keys, r.Header.Get("Idempotency-Key"))
+		f.mu.Unlock()
+
+		if n > len(statuses) {
+			s.Handler.ServeHTTP(w, r)
+			return
+		}
+		status := statuses[n-1]
+		if status == 0 {
+			s.Handler.ServeHTTP(httptest.NewRecorder(), r)
+			status = http.StatusServiceUnavailable
+		}
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *flaky) seen() (int, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts, append([]string(nil), f.keys...)
+}
+
+// expiredAccessToken signs an access token for the user that expired a
+// minute ago, with the server's own key.
+func expiredAccessToken(t *testing.T, s *apptest.Server, id uint, email, role string) string {
+	t.Helper()
+	keys, err := auth.NewKeyManager(auth.KeyConfig{
+		Algorithm: s.Config.JWTAlgorithm,
+		Secret:    s.Config.JWTSecret,
+		Issuer:    s.Config.JWTIssuer,
+		Audience:  s.Config.JWTAudience,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := keys.GenerateToken(id, email, role, []string{"pwd"}, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestLogin(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	c := s.NewClient(t)
+	challenge, err := c.Login(ctx, adminEmail, adminPassword)
+	if err != nil || challenge != nil {
+		t.Fatalf("Login = %+v, %v; want tokens", challenge, err)
+	}
+	if access, refresh := c.Tokens(); access == "" || refresh == "" {
+		t.Fatalf("Tokens() = %q, %q; want both set", access, refresh)
+	}
+
+	me, err := c.Me(ctx)
+	if err != nil {
+		t.Fatalf("Me: %v", err)
+	}
+	if me.Email != adminEmail {
+		t.Errorf("Me().Email = %q, want %q", me.Email, adminEmail)
+	}
+
+	_, err = s.NewClient(t).Login(ctx, adminEmail, "Wrong-Passw0rd")
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Type != "/problems/unauthorized" {
+		t.Fatalf("Login with a wrong password = %v, want a 401 unauthorized problem", err)
+	}
+}
+
+func TestLoginMFAChallenge(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	s.CreateUser(t, "user", "mfa@example.com", "User-Passw0rd")
+
+	c := s.Login(t, "mfa@example.com", "User-Passw0rd")
+	enrolled, err := c.Raw().EnrollTOTPWithResponse(ctx, client.TOTPEnrollRequest{Password: "User-Passw0rd"})
+	if err != nil || enrolled.JSON200 == nil {
+		t.Fatalf("EnrollTOTP: %v", err)
+	}
+	secret := enrolled.JSON200.Secret
+	code, _ := totp.GenerateCode(secret, time.Now())
+	confirmed, err := c.Raw().ConfirmTOTPWithResponse(ctx, client.MFACodeRequest{Code: code})
+	if err != nil || confirmed.JSON200 == nil {
+		t.Fatalf("ConfirmTOTP: %v", err)
+	}
+
+	c = s.NewClient(t)
+	challenge, err := c.Login(ctx, "mfa@example.com", "User-Passw0rd")
+	if err != nil || challenge == nil || challenge.MfaToken == "" {
+		t.Fatalf("Login = %+v, %v; want an MFA challenge", challenge, err)
+	}
+	if access, _ := c.Tokens(); access != "" {
+		t.Fatal("Login kept tokens before the second factor")
+	}
+
+	// Confirming used up the current time step; the next one is accepted as
+	// clock drift.
+	next, _ := totp.GenerateCode(secret, time.Now().Add(30*time.Second))
+	if err := c.VerifyMFA(ctx, client.VerifyMFARequest{MfaToken: challenge.MfaToken, Code: &next}); err != nil {
+		t.Fatalf("VerifyMFA: %v", err)
+	}
+	if _, err := c.Me(ctx); err != nil {
+		t.Fatalf("Me after VerifyMFA: %v", err)
+	}
+}
+
+func TestRefreshAfterUnauthorized(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	_, refresh := s.Login(t, adminEmail, adminPassword).Tokens()
+	stale := expiredAccessToken(t, s, 1, adminEmail, "admin")
+
+	rec := &recorder{next: s.Client()}
+	var changes atomic.Int32
+	c := s.NewClient(t,
+		client.WithHTTPDoer(rec),
+		client.WithTokens(stale, refresh),
+		client.WithTokenCallback(func(string, string) { changes.Add(1) }))
+
+	if _, err := c.Me(ctx); err != nil {
+		t.Fatalf("Me with an expired access token: %v", err)
+	}
+	if got := rec.count("POST /token/refresh"); got != 1 {
+		t.Errorf("refresh requests = %d, want 1", got)
+	}
+	if got := rec.count("GET /me"); got != 2 {
+		t.Errorf("GET /me requests = %d, want 2", got)
+	}
+	if got := changes.Load(); got != 1 {
+		t.Errorf("token callback ran %d times, want 1", got)
+	}
+	if access, newRefresh := c.Tokens(); access == stale || newRefresh == refresh {
+		t.Error("tokens were not replaced by the refresh")
+	}
+}
+
+func TestConcurrentRefreshSharesOneRefreshToken(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	_, refresh := s.Login(t, adminEmail, adminPassword).Tokens()
+	stale := expiredAccessToken(t, s, 1, adminEmail, "admin")
+
+	rec := &recorder{next: s.Client()}
+	c := s.NewClient(t, client.WithHTTPDoer(rec), client.WithTokens(stale, refresh))
+
+	// The server accepts each refresh token once and treats a second use as
+	// theft, so both requests must wait for a single refresh.
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = c.Me(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Errorf("Me: %v", err)
+		}
+	}
+	if got := rec.count("POST /token/refresh"); got != 1 {
+		t.Errorf("refresh requests = %d, want 1", got)
+	}
+}
+
+func TestRetriesTransientStatus(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name       string
+		retryAfter string
+		statuses   []int
+		minWait    time.Duration
+	}{
+		{"503", "", []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}, 0},
+		{"429 with Retry-After", "1", []int{http.StatusTooManyRequests}, time.Second},
+		{"503 with Retry-After", "1", []int{http.StatusServiceUnavailable}, time.Second},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			front := newFlaky(t, s, "/.well-known/jwks.json", tc.retryAfter, tc.statuses...)
+			c, err := client.New(front.URL, client.WithRetries(3, time.Millisecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			resp, err := c.Raw().GetJWKSWithResponse(ctx)
+			if err != nil || resp.JSON200 == nil {
+				t.Fatalf("GetJWKS = %v, %v; want 200", resp.Status(), err)
+			}
+			if got, _ := front.seen(); got != len(tc.statuses)+1 {
+				t.Errorf("attempts = %d, want %d", got, len(tc.statuses)+1)
+			}
+			if waited := time.Since(start); waited < tc.minWait {
+				t.Errorf("retried after %v, want at least %v", waited, tc.minWait)
+			}
+		})
+	}
+
+	t.Run("gives up", func(t *testing.T) {
+		front := newFlaky(t, s, "/.well-known/jwks.json", "", 503, 503, 503, 503)
+		c, err := client.New(front.URL, client.WithRetries(2, time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := c.Raw().GetJWKSWithResponse(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := front.seen(); resp.StatusCode() != http.StatusServiceUnavailable || got != 3 {
+			t.Errorf("got %d after %d attempts, want 503 after 3", resp.StatusCode(), got)
+		}
+	})
+
+	t.Run("not for POST without an Idempotency-Key", func(t *testing.T) {
+		front := newFlaky(t, s, "/login", "", http.StatusServiceUnavailable)
+		c, err := client.New(front.URL, client.WithRetries(3, time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.Login(ctx, adminEmail, adminPassword)
+		var apiErr *client.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Login = %v, want the 503", err)
+		}
+		if got, _ := front.seen(); got != 1 {
+			t.Errorf("attempts = %d, want 1", got)
+		}
+	})
+}
+
+func TestCreateUserIdempotencyKey(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+	access, refresh := s.Login(t, adminEmail, adminPassword).Tokens()
+
+	// The first attempt creates the user but the response never arrives.
+	front := newFlaky(t, s, "/users", "", 0)
+	c, err := client.New(front.URL, client.WithRetries(3, time.Millisecond), client.WithTokens(access, refresh))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := c.CreateUser(ctx, client.CreateUserRequest{
+		Name:     "Idempotent",
+		Email:    openapi_types.Email("idem@example.com"),
+		Phone:    "+14155550199",
+		Password: "User-Passw0rd",
+		Role:     "user",
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if created.Email != "idem@example.com" {
+		t.Errorf("CreateUser().Email = %q, want idem@example.com", created.Email)
+	}
+
+	attempts, keys := front.seen()
+	if attempts != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("CreateUser sent Idempotency-Keys %q, want the same key twice", keys)
+	}
+
+	email := "idem@example.com"
+	page, err := c.ListUsers(ctx, &client.ListUsersParams{Email: &email})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Errorf("found %d users with the email, want 1", len(page.Items))
+	}
+
+	// A second call is a new request with a new key, and clashes.
+	if _, err := c.CreateUser(ctx, client.CreateUserRequest{
+		Name: "Idempotent", Email: "idem@example.com", Phone: "+14155550199", Password: "User-Passw0rd", Role: "user",
+	}); err == nil {
+		t.Error("second CreateUser succeeded, want a conflict")
+	}
+	if _, keys := front.seen(); keys[2] == keys[0] {
+		t.Error("second CreateUser reused the Idempotency-Key of the first")
+	}
+}
+
+func TestUsersIterator(t *testing.T) {
+	s := newServer(t)
+	for i := range 7 {
+		s.CreateUser(t, "user", "user"+strconv.Itoa(i)+"@example.com", "User-Passw0rd")
+	}
+
+	rec := &recorder{next: s.Client()}
+	c := s.Login(t, adminEmail, adminPassword, client.WithHTTPDoer(rec))
+
+	limit := 3
+	seen := make(map[int]bool)
+	for user, err := range c.Users(context.Background(), &client.ListUsersParams{Limit: &limit}) {
+		if err != nil {
+			t.Fatalf("Users: %v", err)
+		}
+		full, err := user.AsUserFull()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[full.Id] {
+			t.Errorf("user %d yielded twice", full.Id)
+		}
+		seen[full.Id] = true
+	}
+
+	if len(seen) != 8 {
+		t.Errorf("Users yielded %d users, want 8", len(seen))
+	}
+	if got := rec.count("GET /users"); got != 3 {
+		t.Errorf("fetched %d pages, want 3", got)
+	}
+}
+
+func TestUsersIteratorStops(t *testing.T) {
+	s := newServer(t)
+	for i := range 4 {
+		s.CreateUser(t, "user", "user"+strconv.Itoa(i)+"@example.com", "User-Passw0rd")
+	}
+
+	rec := &recorder{next: s.Client()}
+	c := s.Login(t, adminEmail, adminPassword, client.WithHTTPDoer(rec))
+
+	limit := 2
+	n := 0
+	for _, err := range c.Users(context.Background(), &client.ListUsersParams{Limit: &limit}) {
+		if err != nil {
+			t.Fatalf("Users: %v", err)
+		}
+		if n++; n == 2 {
+			break
+		}
+	}
+	if got := rec.count("GET /users"); got != 1 {
+		t.Errorf("fetched %d pages after breaking out of the first, want 1", got)
+	}
+}
+
+func TestAuditLogsIterator(t *testing.T) {
+	s := newServer(t)
+	ctx := context.Background()
+
+	rec := &recorder{next: s.Client()}
+	c := s.Login(t, adminEmail, adminPassword, client.WithHTTPDoer(rec))
+	admin, err := c.Me(ctx)
+	if err != nil {
+		t.Fatalf("Me: %v", err)
+	}
+	for i := range 5 {
+		_, err := c.CreateUser(ctx, client.CreateUserRequest{
+			Name:     "Audited",
+			Email:    openapi_types.Email("audited" + strconv.Itoa(i) + "@example.com"),
+			Phone:    "+1415555020" + strconv.Itoa(i),
+			Password: "User-Passw0rd",
+			Role:     "user",
+		})
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+	}
+
+	limit := 2
+	var ids []int
+	for entry, err := range c.AuditLogs(ctx, &client.ListAuditLogsParams{Limit: &limit}) {
+		if err != nil {
+			t.Fatalf("AuditLogs: %v", err)
+		}
+		if entry.Action != "user.create" || entry.ActorUserId == nil || *entry.ActorUserId != admin.Id {
+			t.Errorf("entry %d is %s by %v, want user.create by user %d", entry.Id, entry.Action, entry.ActorUserId, admin.Id)
+		}
+		ids = append(ids, entry.Id)
+	}
+
+	if len(ids) != 5 {
+		t.Fatalf("AuditLogs yielded %d entries, want 5", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] >= ids[i-1] {
+			t.Errorf("entries out of order: %v, want newest first", ids)
+			break
+		}
+	}
+	if got := rec.count("GET /audit"); got != 3 {
+		t.Errorf("fetched %d pages, want 3", got)
+	}
+}
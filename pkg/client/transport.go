@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenStore holds the caller's tokens. Refreshing is serialized because the
+// server only accepts each refresh token once.
+type tokenStore struct {
+	mu         sync.Mutex
+	refreshing sync.Mutex
+	access     string
+	refresh    string
+	onChange   func(access, refresh string)
+}
+
+func (s *tokenStore) get() (access, refresh string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.access, s.refresh
+}
+
+func (s *tokenStore) set(access, refresh string) {
+	s.mu.Lock()
+	s.access, s.refresh = access, refresh
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange(access, refresh)
+	}
+}
+
+// transport adds the bearer token and User-Agent to every request, refreshes
+// the access token once when a request comes back 401, and retries
+// idempotent requests that failed on the network or with a transient status.
+type transport struct {
+	doer       HttpRequestDoer
+	tokens     *tokenStore
+	refresh    func(ctx context.Context, refreshToken string) error
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Do implements HttpRequestDoer.
+func (t *transport) Do(req *http.Request) (*http.Response, error) {
+	// A caller-supplied Authorization header is left alone and never refreshed.
+	if req.Header.Get("Authorization") != "" {
+		return t.send(req, "")
+	}
+
+	access, _ := t.tokens.get()
+	resp, err := t.send(req, access)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || isAuthRequest(req) {
+		return resp, err
+	}
+
+	if !t.refreshed(req.Context(), access) {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	access, _ = t.tokens.get()
+	return t.send(req, access)
+}
+
+// refreshed reports whether there is a newer access token than stale, either
+// because another request already refreshed it or because refreshing now
+// succeeded.
+func (t *transport) refreshed(ctx context.Context, stale string) bool {
+	t.tokens.refreshing.Lock()
+	defer t.tokens.refreshing.Unlock()
+
+	access, refresh := t.tokens.get()
+	if access != stale {
+		return true
+	}
+	if refresh == "" || t.refresh == nil {
+		return false
+	}
+	return t.refresh(ctx, refresh) == nil
+}
+
+// send performs req with access as its bearer token, retrying idempotent
+// requests up to maxRetries times.
+func (t *transport) send(req *http.Request, access string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		try, err := cloneRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		try.Header.Set("User-Agent", UserAgent)
+		if access != "" {
+			try.Header.Set("Authorization", "Bearer "+access)
+		}
+
+		resp, err := t.doer.Do(try)
+		if attempt >= t.maxRetries || !isIdempotent(req) || !isTransient(resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff << attempt
+		if resp != nil {
+			if after := retryAfter(resp); after > 0 {
+				wait = after
+			}
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// cloneRequest copies req with a fresh body so it can be sent again.
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// isIdempotent reports whether req can safely be sent more than once. POST
+// requests qualify when they carry an Idempotency-Key.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// isTransient reports whether a failed attempt is worth retrying.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	// A concurrent request with the same Idempotency-Key is still running.
+	return resp.StatusCode == http.StatusConflict && resp.Header.Get("Retry-After") != ""
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// isAuthRequest reports whether req obtains tokens rather than uses them, so
+// a 401 from it means bad credentials rather than an expired access token.
+func isAuthRequest(req *http.Request) bool {
+	path := req.URL.Path
+	return strings.HasSuffix(path, "/login") || strings.HasSuffix(path, "/login/mfa") || strings.HasSuffix(path, "/token/refresh")
+}
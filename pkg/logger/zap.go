@@ -2,13 +2,15 @@ package logger
 
 import (
 	"context"
+	"go-crud-oapi/pkg/requestctx"
 	"os"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var zapLog *zap.Logger
+// zapLog discards everything until Init, so packages can be used in tests.
+var zapLog = zap.NewNop()
 
 func Init() {
 	logFile := "logs/app.log"
@@ -53,7 +55,7 @@ func Init() {
 
 // L returns a logger enriched with request ID from context
 func L(ctx context.Context) *zap.Logger {
-	if requestID, ok := ctx.Value("requestID").(string); ok {
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
 		return zapLog.With(zap.String("request_id", requestID))
 	}
 	return zapLog
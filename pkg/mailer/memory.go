@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errHeaderInjection = errors.New("mailer: line break in header field")
+
+// memoryMailerLimit is how many messages a MemoryMailer retains.
+const memoryMailerLimit = 100
+
+// MemoryMailer keeps the most recent sent messages in memory instead of
+// delivering them, for tests. Older messages are dropped.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == memoryMailerLimit {
+		m.sent = append(m.sent[:0], m.sent[1:]...)
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Messages returns a copy of the retained messages, oldest first.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Last returns the most recent message sent to address, if any.
+func (m *MemoryMailer) Last(address string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.sent) - 1; i >= 0; i-- {
+		if m.sent[i].To == address {
+			return m.sent[i], true
+		}
+	}
+	return Message{}, false
+}
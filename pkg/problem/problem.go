@@ -0,0 +1,118 @@
+// Package problem writes error responses as RFC 7807 problem details.
+package problem
+
+import (
+	"encoding/json"
+	"go-crud-oapi/pkg/requestctx"
+	"go-crud-oapi/pkg/validation"
+	"net/http"
+)
+
+// ContentType is the media type of every error response.
+const ContentType = "application/problem+json"
+
+// Problem type URIs. They are relative references, resolved against the
+// API's base URL. TypeBlank means the status code says all there is to say.
+const (
+	TypeBlank          = "about:blank"
+	TypeInvalidRequest = "/problems/invalid-request"
+	TypeValidation     = "/problems/validation-failed"
+	TypeUnauthorized   = "/problems/unauthorized"
+	TypeForbidden      = "/problems/forbidden"
+	TypeNotFound       = "/problems/not-found"
+	TypeConflict       = "/problems/conflict"
+)
+
+var titles = map[string]string{
+	TypeInvalidRequest: "Invalid request",
+	TypeValidation:     "Validation failed",
+	TypeUnauthorized:   "Authentication required",
+	TypeForbidden:      "Permission denied",
+	TypeNotFound:       "Resource not found",
+	TypeConflict:       "Resource conflict",
+}
+
+// Problem is a problem details object. Extensions are serialised as extra
+// top-level members next to the standard ones.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// New returns a problem of type typ with the title registered for it.
+func New(typ string, status int, detail string) *Problem {
+	title, ok := titles[typ]
+	if !ok {
+		title = http.StatusText(status)
+	}
+	return &Problem{Type: typ, Title: title, Status: status, Detail: detail}
+}
+
+// Status returns an about:blank problem, titled with the status text.
+func Status(status int, detail string) *Problem {
+	return New(TypeBlank, status, detail)
+}
+
+// InvalidRequest is a 400 for a request that could not be understood.
+func InvalidRequest(detail string) *Problem {
+	return New(TypeInvalidRequest, http.StatusBadRequest, detail)
+}
+
+// Validation is a 422 listing the fields that broke a rule.
+func Validation(fields []validation.FieldError) *Problem {
+	return New(TypeValidation, http.StatusUnprocessableEntity, "One or more fields are invalid.").With("errors", fields)
+}
+
+// Unauthorized is a 401 for a caller that is not, or no longer, authenticated.
+func Unauthorized(detail string) *Problem {
+	return New(TypeUnauthorized, http.StatusUnauthorized, detail)
+}
+
+// Forbidden is a 403 for an authenticated caller that may not do this.
+func Forbidden(detail string) *Problem {
+	return New(TypeForbidden, http.StatusForbidden, detail)
+}
+
+// With sets the extension member key and returns p.
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// Write sends p as the response. Its instance is the request ID, so a client
+// report can be matched with the server logs.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Instance == "" {
+		if id := requestctx.RequestID(r.Context()); id != "" {
+			p.Instance = "urn:uuid:" + id
+		}
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,39 @@
+package requestctx
+
+import "context"
+
+type ctxKey string
+
+const (
+	requestIDKey ctxKey = "requestID"
+	actorKey     ctxKey = "actor"
+)
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// Caller identifies who is making a request: a user and, if they
+// authenticated with one, their API token.
+type Caller struct {
+	UserID     uint
+	APITokenID uint
+}
+
+// WithActor returns a copy of ctx carrying the authenticated caller.
+func WithActor(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, actorKey, caller)
+}
+
+// Actor returns the authenticated caller stored in ctx, or the zero Caller.
+func Actor(ctx context.Context) Caller {
+	caller, _ := ctx.Value(actorKey).(Caller)
+	return caller
+}
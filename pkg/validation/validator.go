@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a single rule that a field failed.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error is returned when a struct fails one or more validation rules.
+type Error struct {
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+" "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name so errors match the request body.
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return strings.ToLower(fld.Name)
+		}
+		if name == "" {
+			return fld.Name
+		}
+		return name
+	})
+
+	if err := v.RegisterValidation("password", strongPassword); err != nil {
+		panic("Failed to register password rule: " + err.Error())
+	}
+	return v
+}
+
+// Struct runs the `validate` tags on s, skipping the named struct fields.
+// It returns an *Error when any rule fails.
+func Struct(s any, except ...string) error {
+	var err error
+	if len(except) > 0 {
+		err = validate.StructExcept(s, except...)
+	} else {
+		err = validate.Struct(s)
+	}
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	out := &Error{Fields: make([]FieldError, 0, len(verrs))}
+	for _, fe := range verrs {
+		out.Fields = append(out.Fields, FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+	return out
+}
+
+// MaxPasswordBytes is the longest password accepted. bcrypt refuses to hash
+// anything longer.
+const MaxPasswordBytes = 72
+
+// strongPassword requires at least 8 characters with an upper case letter,
+// a lower case letter and a digit, and at most MaxPasswordBytes bytes.
+func strongPassword(fl validator.FieldLevel) bool {
+	pw := fl.Field().String()
+	if len(pw) < 8 || len(pw) > MaxPasswordBytes {
+		return false
+	}
+	var upper, lower, digit bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "e164":
+		return "must be an E.164 phone number, e.g. +14155552671"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "password":
+		return fmt.Sprintf("must be 8 characters to %d bytes long and contain an upper case letter, a lower case letter and a digit", MaxPasswordBytes)
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
+	}
+}